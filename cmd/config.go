@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and edit sanoja configuration",
+	Long: `Show and edit sanoja configuration.
+
+Settings are read from $XDG_CONFIG_HOME/sanoja/config.yaml (or config.yml,
+config.toml; SANOJA_CONFIG points to another file) and from SANOJA_*
+environment variables. Flags given on the command line always win, then
+environment variables, then the config file, then built-in defaults.
+
+Keys are flag names. Global flags are top-level keys; flags of a command live
+in a section named after it, e.g. serve.port, ytt.lang or stock.json. The
+environment variable for a key is SANOJA_ followed by the key in upper case
+with dots and dashes replaced by underscores, e.g. SANOJA_SERVE_PORT.
+
+Examples:
+  sanoja config path                   # Show the config file in use
+  sanoja config set serve.port 8080    # Set the default server port
+  sanoja config set ytt.lang fi,en     # Prefer Finnish, then English transcripts
+  sanoja config get proxy              # Show the effective proxy setting
+  sanoja config show                   # Show all settings and where they come from`,
+	// Editing the config must keep working even if the current file is invalid
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print the effective value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		key := args[0]
+		if value, _, ok := cfg.Lookup(key); ok {
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		}
+
+		// Fall back to the flag's built-in default
+		settings := knownSettings()
+		if flag, ok := settings[key]; ok {
+			fmt.Fprintln(cmd.OutOrStdout(), flag.DefValue)
+			return nil
+		}
+
+		return fmt.Errorf("%s is not set", key)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Store a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+
+		// Validate the value against the flag it configures, if there is
+		// one, and store it with the flag's type
+		kind := "string"
+		if flag, ok := knownSettings()[key]; ok {
+			if err := validateSetting(flag, value); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %v", value, key, err)
+			}
+			kind = flag.Value.Type()
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s does not correspond to any flag\n", key)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(key, value, kind); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s in %s\n", key, value, cfg.Path())
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show all settings with their values and sources",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		settings := knownSettings()
+		keys := make(map[string]bool)
+		for key := range settings {
+			keys[key] = true
+		}
+		for _, key := range cfg.Keys() {
+			keys[key] = true
+		}
+
+		var sorted []string
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		out := cmd.OutOrStdout()
+		for _, key := range sorted {
+			if value, source, ok := cfg.Lookup(key); ok {
+				fmt.Fprintf(out, "%s = %s (%s)\n", key, value, source)
+			} else if flag, ok := settings[key]; ok {
+				fmt.Fprintf(out, "%s = %s (default)\n", key, flag.DefValue)
+			}
+		}
+		return nil
+	},
+}
+
+// knownSettings maps every config key to the flag it configures
+func knownSettings() map[string]*pflag.Flag {
+	settings := make(map[string]*pflag.Flag)
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		// Persistent flags are listed under the command that defines them
+		cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Name != "help" && !flag.Hidden {
+				settings[configKey(cmd, flag)] = flag
+			}
+		})
+		for _, child := range cmd.Commands() {
+			// Skip commands that have no settings of their own
+			if child != configCmd && child.Name() != "completion" && child.Name() != "help" {
+				walk(child)
+			}
+		}
+	}
+	walk(rootCmd)
+	return settings
+}
+
+// validateSetting checks that value parses as the type of the flag it configures
+func validateSetting(flag *pflag.Flag, value string) error {
+	var err error
+	switch flag.Value.Type() {
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "int", "int64":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	case "duration":
+		_, err = time.ParseDuration(value)
+	}
+	return err
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd, configGetCmd, configSetCmd, configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
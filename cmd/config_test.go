@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigKey(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"proxy", "proxy"},
+		{"concurrency", "stock.concurrency"},
+		{"interval", "stock.alert.run.interval"},
+	}
+	for _, tt := range tests {
+		flag := stockAlertRunCmd.LocalFlags().Lookup(tt.flag)
+		if flag == nil {
+			flag = stockAlertRunCmd.InheritedFlags().Lookup(tt.flag)
+		}
+		if got := configKey(stockAlertRunCmd, flag); got != tt.want {
+			t.Errorf("configKey(stock alert run, %s) = %s, want %s", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		key, value string
+	}{
+		{"stock.concurrency", "8"},
+		{"id.nanoid.alphabet", "0123456789"},
+		{"ytt.lang", "fi,en"},
+		{"ytt.window", "1m0s"},
+	}
+	for _, tt := range tests {
+		out, err := runCommand(t, "", "config", "set", tt.key, tt.value)
+		if err != nil || strings.Contains(out, "Warning") {
+			t.Errorf("config set %s %s = %v\n%s", tt.key, tt.value, err, out)
+		}
+		out, err = runCommand(t, "", "config", "get", tt.key)
+		if err != nil || out != tt.value+"\n" {
+			t.Errorf("config get %s = %q, %v, want %s", tt.key, out, err, tt.value)
+		}
+	}
+
+	// A persistent flag reads its setting in the subcommands that inherit it
+	runCommand(t, "", "stock", "alert", "list")
+	if stockConcurrency != 8 {
+		t.Errorf("stock alert list has --concurrency %d, want 8 from the config", stockConcurrency)
+	}
+}
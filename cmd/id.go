@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mjlefevre/sanoja/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+var (
+	idAlphabet string
+	idSize     int
+	idNode     int64
+	idEpoch    string
+	idType     string
+)
+
+// idDescriptions are the short help of the generator subcommands
+var idDescriptions = map[string]string{
+	"uuid":      "Generate UUIDs",
+	"ulid":      "Generate ULIDs, sortable by millisecond",
+	"nanoid":    "Generate NanoIDs, short random IDs",
+	"ksuid":     "Generate KSUIDs, sortable by second",
+	"snowflake": "Generate Snowflake IDs, 63-bit integers sortable by millisecond",
+}
+
+var idCmd = &cobra.Command{
+	Use:   "id",
+	Short: "Generate and inspect unique IDs",
+	Long: `Generate unique IDs in several formats and extract the time embedded in them.
+
+Formats:
+  uuid       RFC 9562 UUIDs, see "sanoja uuid --help" for versions
+  ulid       26 characters, 48-bit millisecond time and 80 random bits
+  nanoid     21 URL-safe random characters by default
+  ksuid      27 characters, 32-bit second time and 128 random bits
+  snowflake  decimal 63-bit integer of time, node and sequence number
+
+ULIDs, KSUIDs, Snowflake IDs and v1, v6 and v7 UUIDs sort by creation time.
+
+Examples:
+  sanoja id ulid -n 10
+  sanoja id nanoid --size 10 --alphabet 0123456789abcdef
+  sanoja id snowflake --node 42
+  sanoja id uuid --version 7
+  sanoja id time 01ARZ3NDEKTSV4RRFFQ69G5FAV`,
+	Args: cobra.NoArgs,
+}
+
+var idTimeCmd = &cobra.Command{
+	Use:   "time ID...",
+	Short: "Show the creation time embedded in IDs",
+	Long: `Show the creation time embedded in ULIDs, KSUIDs, Snowflake IDs and
+v1, v6 and v7 UUIDs. The type of each ID is detected from its format unless
+--type is given.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := snowflakeOptions()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		defer w.Flush()
+		for _, s := range args {
+			typ := idType
+			if typ == "" {
+				var ok bool
+				if typ, ok = id.Detect(s); !ok {
+					return fmt.Errorf("could not detect the type of %q, use --type", s)
+				}
+			}
+			t, err := id.Time(typ, s, opts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s, typ, t.Format(time.RFC3339Nano))
+		}
+		return nil
+	},
+}
+
+// newIDCmd creates the subcommand generating IDs of one type
+func newIDCmd(typ string) *cobra.Command {
+	short := idDescriptions[typ]
+	if short == "" {
+		short = "Generate " + typ + " IDs"
+	}
+	cmd := &cobra.Command{
+		Use:   typ,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runID(cmd, typ)
+		},
+	}
+	cmd.Flags().IntVarP(&count, "number", "n", 1, "Number of IDs to generate")
+	return cmd
+}
+
+// runID prints count IDs of the given type, configured by the command's flags
+func runID(cmd *cobra.Command, typ string) error {
+	if count < 1 {
+		return fmt.Errorf("number of IDs must be positive, got %d", count)
+	}
+
+	var opts id.Options
+	var err error
+	switch typ {
+	case "uuid":
+		opts, err = uuidOptions(cmd)
+	case "nanoid":
+		opts = id.Options{Alphabet: idAlphabet, Size: idSize}
+	case "snowflake":
+		opts, err = snowflakeOptions()
+		opts.Node = idNode
+	}
+	if err != nil {
+		return err
+	}
+
+	ids, err := id.Generate(typ, opts, count)
+	if err != nil {
+		return err
+	}
+	if typ == "ulid" || typ == "uuid" && opts.Version == 7 {
+		if err := checkMonotonic(ids); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(ids, "\n"))
+	return nil
+}
+
+// uuidOptions validates the UUID flags shared by "uuid" and "id uuid"
+func uuidOptions(cmd *cobra.Command) (id.Options, error) {
+	opts := id.Options{Version: uuidVersion, Name: uuidName}
+	if uuidVersion != 3 && uuidVersion != 5 {
+		if cmd.Flags().Changed("namespace") || cmd.Flags().Changed("name") {
+			return opts, fmt.Errorf("--namespace and --name only apply to version 3 and 5 UUIDs")
+		}
+		return opts, nil
+	}
+
+	if uuidName == "" {
+		return opts, fmt.Errorf("version %d UUIDs require --name", uuidVersion)
+	}
+	if count > 1 {
+		return opts, fmt.Errorf("version %d UUIDs are deterministic, so only one can be generated per name", uuidVersion)
+	}
+	var err error
+	opts.Namespace, err = id.ParseNamespace(uuidNamespace)
+	return opts, err
+}
+
+func snowflakeOptions() (id.Options, error) {
+	var opts id.Options
+	if idEpoch != "" {
+		epoch, err := time.Parse(time.RFC3339, idEpoch)
+		if err != nil {
+			return opts, fmt.Errorf("invalid epoch %q, expected RFC 3339 like 2010-11-04T01:42:54.657Z", idEpoch)
+		}
+		opts.Epoch = epoch
+	}
+	return opts, nil
+}
+
+// addUUIDFlags adds the UUID version flags to "uuid" and "id uuid"
+func addUUIDFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&uuidVersion, "version", "v", 4, "UUID version: 1, 3, 4, 5, 6 or 7")
+	cmd.Flags().StringVar(&uuidNamespace, "namespace", "dns", "Namespace for version 3 and 5: dns, url, oid, x500 or a UUID")
+	cmd.Flags().StringVar(&uuidName, "name", "", "Name for version 3 and 5 UUIDs")
+}
+
+func init() {
+	rootCmd.AddCommand(idCmd)
+	for _, typ := range id.Types() {
+		sub := newIDCmd(typ)
+		switch typ {
+		case "uuid":
+			addUUIDFlags(sub)
+		case "nanoid":
+			sub.Flags().StringVar(&idAlphabet, "alphabet", id.DefaultNanoIDAlphabet, "Characters to draw from")
+			sub.Flags().IntVar(&idSize, "size", id.DefaultNanoIDSize, "Number of characters")
+		case "snowflake":
+			sub.Flags().Int64Var(&idNode, "node", 0, fmt.Sprintf("Node number of this generator, 0 to %d", id.MaxSnowflakeNode))
+			sub.Flags().StringVar(&idEpoch, "epoch", "", "Epoch of the timestamps (default Twitter's, "+id.DefaultSnowflakeEpoch.Format(time.RFC3339Nano)+")")
+		}
+		idCmd.AddCommand(sub)
+	}
+
+	idCmd.AddCommand(idTimeCmd)
+	idTimeCmd.Flags().StringVar(&idType, "type", "", "ID type: "+strings.Join(id.Types(), ", ")+" (default detected)")
+	idTimeCmd.Flags().StringVar(&idEpoch, "epoch", "", "Epoch of Snowflake timestamps (default Twitter's)")
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIDGenerators(t *testing.T) {
+	tests := []struct {
+		args   []string
+		length int
+	}{
+		{[]string{"id", "ulid", "-n", "3"}, 26},
+		{[]string{"id", "ksuid", "-n", "3"}, 27},
+		{[]string{"id", "nanoid", "-n", "3", "--size", "10"}, 10},
+		{[]string{"id", "uuid", "-n", "3", "--version", "7"}, 36},
+		{[]string{"id", "snowflake", "-n", "3", "--node", "7"}, 19},
+	}
+	for _, tt := range tests {
+		out, err := runCommand(t, "", tt.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		ids := strings.Fields(out)
+		if len(ids) != 3 {
+			t.Fatalf("%v: got %d IDs, want 3", tt.args, len(ids))
+		}
+		for _, id := range ids {
+			if len(id) != tt.length {
+				t.Errorf("%v: ID %q has length %d, want %d", tt.args, id, len(id), tt.length)
+			}
+		}
+	}
+
+	if _, err := runCommand(t, "", "id", "snowflake", "--node", "1024"); err == nil {
+		t.Error("expected an error for an out of range node")
+	}
+}
+
+func TestIDTime(t *testing.T) {
+	out, err := runCommand(t, "", "id", "time", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "1541815603606036480", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV            ulid       2016-07-30T23:54:10.259Z",
+		"1541815603606036480                   snowflake  2022-06-28T16:07:40.105Z",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f  uuid       2022-02-22T19:22:22Z",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	// A custom epoch shifts Snowflake times
+	out, err = runCommand(t, "", "id", "time", "--type", "snowflake", "--epoch", "2020-01-01T00:00:00Z", "4194304000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "2020-01-01T00:00:01Z") {
+		t.Errorf("unexpected output with custom epoch:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "", "id", "time", "not an id"); err == nil {
+		t.Error("expected an error for an undetectable ID")
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandwiki(t *testing.T) {
+	out, err := runCommand(t, "randwiki", "randwiki")
+	if err != nil {
+		t.Fatalf("randwiki failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "Title: Sauna\n") {
+		t.Errorf("unexpected title line in:\n%s", out)
+	}
+	for _, unwanted := range []string{"<style", "<script", "mw-editsection", "cite_ref", "navbox", "lead section"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output should not contain %q", unwanted)
+		}
+	}
+	if !strings.Contains(out, "<b>sauna</b>") {
+		t.Errorf("expected article HTML in the output, got:\n%s", out)
+	}
+}
+
+func TestRandwikiTextOnly(t *testing.T) {
+	out, err := runCommand(t, "randwiki", "randwiki", "-t")
+	if err != nil {
+		t.Fatalf("randwiki failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "<") {
+		t.Errorf("text output should not contain HTML:\n%s", out)
+	}
+	if !strings.Contains(out, "designed as a place to experience dry or wet heat sessions. The steam") {
+		t.Errorf("expected lead paragraph without reference markers, got:\n%s", out)
+	}
+}
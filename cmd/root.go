@@ -1,7 +1,26 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mjlefevre/sanoja/internal/browser"
+	"github.com/mjlefevre/sanoja/internal/config"
+	"github.com/mjlefevre/sanoja/internal/httpx"
+	"github.com/mjlefevre/sanoja/pkg/words"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// httpOptions holds the persistent HTTP flags shared by every command
+	httpOptions = httpx.DefaultOptions()
+	cacheDir    string
+
+	// configuredFlags are the flags of the running command filled in from
+	// the environment or the config file rather than the command line
+	configuredFlags map[*pflag.Flag]bool
 )
 
 var rootCmd = &cobra.Command{
@@ -11,7 +30,25 @@ var rootCmd = &cobra.Command{
     
 Example usage:
   sanoja ytt https://www.youtube.com/watch?v=abc123xyz
-  sanoja ytt abc123xyz`,
+  sanoja ytt abc123xyz
+
+Configuration:
+  Defaults for any flag can be set in $XDG_CONFIG_HOME/sanoja/config.yaml
+  (or config.toml) and in SANOJA_* environment variables. Global flags are
+  top-level keys, command flags live in a section named after the command:
+
+    proxy: http://localhost:8080
+    serve:
+      port: 8080
+    ytt:
+      lang: [fi, en]
+
+  Precedence, highest first: flags, environment (SANOJA_PROXY,
+  SANOJA_SERVE_PORT, SANOJA_YTT_LANG), config file, built-in defaults.
+  See "sanoja config --help".`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyConfig(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
@@ -23,4 +60,101 @@ func Execute() error {
 
 func init() {
 	rootCmd.AddCommand(yttCmd)
+
+	flags := rootCmd.PersistentFlags()
+	flags.DurationVar(&httpOptions.Timeout, "timeout", httpOptions.Timeout, "Timeout for each HTTP request, including retries")
+	flags.IntVar(&httpOptions.Retries, "retries", httpOptions.Retries, "Number of times to retry failed HTTP requests")
+	flags.StringVar(&httpOptions.Proxy, "proxy", "", "Proxy URL for HTTP requests (default from HTTP_PROXY/HTTPS_PROXY)")
+	flags.StringVar(&httpOptions.CookiesFile, "cookies", "", "Netscape-format cookies.txt file to send with requests")
+	flags.StringVar(&httpOptions.Profile, "profile", "",
+		"Browser profile to present: "+strings.Join(browser.ProfileNames(), ", ")+", random, sticky, or a JSON profile file (default "+browser.DefaultProfileName+")")
+	flags.Int64Var(&httpOptions.MaxBodySize, "max-body-size", httpOptions.MaxBodySize, "Maximum HTTP response body size in bytes (0 for no limit)")
+	flags.BoolVar(&httpOptions.Debug, "debug", false, "Log HTTP requests and responses to stderr")
+	flags.StringVar(&cacheDir, "cache-dir", "", "Directory to cache fetched transcripts in (caching is off if empty)")
+
+	// Record/replay of HTTP interactions, used to build offline test fixtures
+	flags.StringVar(&httpOptions.Record, "record", "", "Record HTTP interactions to cassette directory DIR")
+	flags.StringVar(&httpOptions.Replay, "replay", "", "Replay HTTP interactions from cassette directory DIR")
+	flags.MarkHidden("record")
+	flags.MarkHidden("replay")
+}
+
+// configKey returns the config key for a flag of the given command: global flags
+// are top-level keys, command flags are prefixed by the path of the command
+// that defines them, so that a persistent flag has the same key in every
+// subcommand that inherits it
+func configKey(cmd *cobra.Command, flag *pflag.Flag) string {
+	owner := cmd
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.PersistentFlags().Lookup(flag.Name) == flag {
+			owner = c
+			break
+		}
+	}
+	section := strings.Fields(owner.CommandPath())[1:]
+	return strings.Join(append(section, flag.Name), ".")
+}
+
+// applyConfig fills in flags not given on the command line from the
+// environment and the config file
+func applyConfig(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	configuredFlags = make(map[*pflag.Flag]bool)
+	var errs []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed || flag.Name == "help" {
+			return
+		}
+		key := configKey(cmd, flag)
+		value, source, ok := cfg.Lookup(key)
+		if !ok {
+			return
+		}
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value %q for %s from %s: %v", value, key, source, err))
+		}
+		configuredFlags[flag] = true
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// flagGiven reports whether a flag was given on the command line, as opposed
+// to left at its default or filled in from the environment or config file
+func flagGiven(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flags().Lookup(name)
+	return flag != nil && flag.Changed && !configuredFlags[flag]
+}
+
+// explicitLanguage returns the declared language of a document if it is
+// one of the languages given with --lang on the command line, so that it can
+// be trusted over the detection. It returns "" otherwise, e.g. when YouTube
+// fell back to a track in another language.
+func explicitLanguage(cmd *cobra.Command, doc *words.Document, languages []string) string {
+	if !flagGiven(cmd, "lang") || doc.Lang == "" {
+		return ""
+	}
+	base := func(lang string) string {
+		if i := strings.IndexAny(lang, "-_"); i >= 0 {
+			lang = lang[:i]
+		}
+		return strings.ToLower(lang)
+	}
+	for _, lang := range languages {
+		if base(lang) == base(doc.Lang) {
+			return doc.Lang
+		}
+	}
+	return ""
+}
+
+// newHTTPClient returns an HTTP client configured from the persistent root flags
+func newHTTPClient() (*http.Client, error) {
+	return httpx.New(httpOptions)
 }
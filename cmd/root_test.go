@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the developer's config and environment out of the tests
+	dir, err := os.MkdirTemp("", "sanoja-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, env := range os.Environ() {
+		if name, _, _ := strings.Cut(env, "="); strings.HasPrefix(name, "SANOJA_") {
+			os.Unsetenv(name)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// runCommand executes sanoja with the given arguments against a recorded
+// cassette from testdata/cassettes and returns what it printed
+func runCommand(t *testing.T, cassette string, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+
+	if cassette != "" {
+		args = append([]string{"--replay", filepath.Join("testdata", "cassettes", cassette)}, args...)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+// resetFlags restores every flag to its default, since cobra keeps flag
+// values in package variables between executions
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			defaults := strings.Trim(flag.DefValue, "[]")
+			if defaults == "" {
+				slice.Replace(nil)
+			} else {
+				slice.Replace(strings.Split(defaults, ","))
+			}
+			// Slice flags append to their value once set, so make the next
+			// Set replace the defaults again
+			if fresh, ok := flag.Value.(*freshSlice); ok {
+				fresh.fresh = true
+			} else {
+				flag.Value = &freshSlice{Value: flag.Value, slice: slice, fresh: true}
+			}
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// freshSlice is a slice flag whose first Set replaces its value, as it does
+// for a flag that was never set before
+type freshSlice struct {
+	pflag.Value
+	slice pflag.SliceValue
+	fresh bool
+}
+
+func (f *freshSlice) Set(value string) error {
+	if !f.fresh {
+		return f.Value.Set(value)
+	}
+	f.fresh = false
+	if value == "" {
+		return f.slice.Replace(nil)
+	}
+	values, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return err
+	}
+	return f.slice.Replace(values)
+}
+
+func (f *freshSlice) Append(value string) error     { return f.slice.Append(value) }
+func (f *freshSlice) Replace(values []string) error { return f.slice.Replace(values) }
+func (f *freshSlice) GetSlice() []string            { return f.slice.GetSlice() }
+
+func TestResetFlags(t *testing.T) {
+	if _, err := runCommand(t, "ytt", "ytt", "--lang", "fi", "k82RwXqZHY8"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, "ytt", "ytt", "--lang", "sv,fi", "k82RwXqZHY8"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(yttLanguages, ",") != "sv,fi" {
+		t.Errorf("--lang = %v, want the value of the second run only", yttLanguages)
+	}
+
+	if _, err := runCommand(t, "ytt", "ytt", "k82RwXqZHY8"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(yttLanguages, ",") != "en" {
+		t.Errorf("--lang = %v, want the default back", yttLanguages)
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/mjlefevre/sanoja/internal/handlers"
+	"github.com/mjlefevre/sanoja/pkg/stock"
+	"github.com/mjlefevre/sanoja/pkg/transcript"
 	"github.com/spf13/cobra"
 )
 
 var (
-	port int
+	port     int
+	stockTTL time.Duration
 )
 
 var serveCmd = &cobra.Command{
@@ -22,18 +26,46 @@ By default, the server runs on port 3000. Use --port flag to specify a different
 The server exposes the following endpoints:
   GET /ytt - Get YouTube video transcripts
   GET /ytt?help - View API documentation
+  GET /ytt?v=VIDEO_ID&summary=5 - Summarize a transcript in its most central sentences
+  GET /api/v1/stocks/{symbol} - Get a stock quote as JSON
+  GET /api/v1/stocks?symbols=A,B,C - Get several stock quotes as JSON
+  GET /stocks?symbols=A,B,C - Auto-refreshing stock dashboard
+  GET /stocks?list=NAME - Dashboard of a saved watchlist
+  GET /api/v1/ids?type=ulid&n=10 - Generate IDs of type uuid, ulid, nanoid, ksuid or snowflake
+  GET /api/v1/videos/{id}?lang=fi&keywords=10 - Transcript tracks, detected language, speaking rate and key phrases of a video
+  GET /api/v1/videos/{id}/timeline?window=30&step=5 - Speaking rate, gaps and bursts of a video as JSON
+
+Stock quotes are cached in memory for --stock-ttl to avoid hammering Yahoo Finance.
 
 Example:
   sanoja serve
-  sanoja serve --port 8080`,
+  sanoja serve --port 8080
+  sanoja serve --stock-ttl 5m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		addr := fmt.Sprintf(":%d", port)
 
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+
 		// Create handlers
-		transcriptHandler := handlers.NewTranscriptHandler(port)
+		transcriptHandler := handlers.NewTranscriptHandler(port, transcript.NewClient(transcript.WithHTTPClient(httpClient)))
+
+		watchlists, err := watchlistsPath()
+		if err != nil {
+			return err
+		}
+		stockHandler := handlers.NewStockHandler(stock.NewClient(stock.WithHTTPClient(httpClient)), stockTTL, watchlists)
 
 		// Setup routes
 		http.HandleFunc("/ytt", transcriptHandler.GetTranscript)
+		http.HandleFunc("GET /api/v1/stocks/{symbol}", stockHandler.GetStock)
+		http.HandleFunc("GET /api/v1/stocks", stockHandler.GetStocks)
+		http.HandleFunc("GET /stocks", stockHandler.Dashboard)
+		http.HandleFunc("GET /api/v1/ids", handlers.NewIDHandler().GetIDs)
+		http.HandleFunc("GET /api/v1/videos/{id}", transcriptHandler.GetVideo)
+		http.HandleFunc("GET /api/v1/videos/{id}/timeline", transcriptHandler.GetTimeline)
 
 		log.Printf("Starting server on http://localhost%s", addr)
 		return http.ListenAndServe(addr, nil)
@@ -42,5 +74,6 @@ Example:
 
 func init() {
 	serveCmd.Flags().IntVarP(&port, "port", "p", 3000, "Port to run the server on")
+	serveCmd.Flags().DurationVar(&stockTTL, "stock-ttl", time.Minute, "How long stock quotes are cached, 0 to disable")
 	rootCmd.AddCommand(serveCmd)
 }
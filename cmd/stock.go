@@ -1,77 +1,289 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
-	"net/http"
-	"strings"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
-	"github.com/mjlefevre/sanoja/internal/browser"
+	"github.com/mjlefevre/sanoja/internal/config"
+	"github.com/mjlefevre/sanoja/pkg/stock"
 	"github.com/spf13/cobra"
 )
 
+var (
+	stockJSON        bool
+	stockCSV         bool
+	stockStats       bool
+	stockList        string
+	stockSaveList    string
+	stockWatch       time.Duration
+	stockConcurrency int
+)
+
 var stockCmd = &cobra.Command{
-	Use:   "stock [SYMBOL]",
+	Use:   "stock [SYMBOL...]",
 	Short: "Get stock information from Yahoo Finance",
 	Long: `Get stock information from Yahoo Finance.
 
+Watchlists are stored in watchlists.yaml next to the config file
+(see "sanoja config path").
+
 Example:
-  sanoja stock AAPL    # Get Apple stock information`,
-	Args: cobra.ExactArgs(1),
+  sanoja stock AAPL                          # Get Apple stock information
+  sanoja stock AAPL --json                   # Output the quote as JSON
+  sanoja stock AAPL --csv                    # Output the quote as CSV
+  sanoja stock AAPL --stats                  # Include key statistics such as P/E and market cap
+  sanoja stock AAPL MSFT NVDA                # Show a table of several quotes
+  sanoja stock AAPL MSFT --save-list tech    # Save the symbols as watchlist "tech"
+  sanoja stock --list tech                   # Show the quotes of watchlist "tech"
+  sanoja stock --list tech --watch 30s       # Refresh the table every 30 seconds`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		symbol := strings.ToUpper(args[0])
-		url := fmt.Sprintf("https://finance.yahoo.com/quote/%s", symbol)
+		if stockJSON && stockCSV {
+			return fmt.Errorf("--json and --csv cannot be used together")
+		}
 
-		// Create request with headers
-		req, err := http.NewRequest("GET", url, nil)
+		symbols, err := stockSymbols(args)
 		if err != nil {
-			return fmt.Errorf("error creating request: %v", err)
+			return err
+		}
+		if len(symbols) == 0 {
+			return fmt.Errorf("no symbols given; pass symbols as arguments or use --list")
 		}
 
-		browser.SetDefaultHeaders(req)
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+		client := stock.NewClient(stock.WithHTTPClient(httpClient))
 
-		// Create client with timeout
-		client := &http.Client{
-			Timeout: 10 * time.Second,
+		if stockWatch > 0 {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			return watchQuotes(ctx, cmd.OutOrStdout(), client, symbols)
 		}
 
-		// Get the page
-		resp, err := client.Do(req)
-		if err != nil {
-			return fmt.Errorf("error fetching page: %v", err)
+		results := client.GetQuotes(symbols, stockConcurrency)
+		if len(symbols) == 1 && results[0].Err != nil {
+			return results[0].Err
+		}
+		if err := writeQuotes(cmd.OutOrStdout(), results, len(symbols) > 1); err != nil {
+			return err
 		}
-		defer resp.Body.Close()
+		return quoteErrors(cmd.ErrOrStderr(), results)
+	},
+}
 
-		// Parse HTML
-		doc, err := goquery.NewDocumentFromReader(resp.Body)
+// stockSymbols combines the symbols given as arguments with those of the
+// --list watchlist, and saves them when --save-list is given
+func stockSymbols(args []string) ([]string, error) {
+	symbols := args
+	if stockList == "" && stockSaveList == "" {
+		return stock.NormalizeSymbols(symbols), nil
+	}
+
+	path, err := watchlistsPath()
+	if err != nil {
+		return nil, err
+	}
+	lists, err := stock.LoadWatchlists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if stockList != "" {
+		saved, err := lists.Get(stockList)
 		if err != nil {
-			return fmt.Errorf("error parsing page: %v", err)
+			return nil, err
 		}
+		symbols = append(saved, symbols...)
+	}
+	symbols = stock.NormalizeSymbols(symbols)
 
-		// Get title from main h1
-		title := doc.Find("main h1").First().Text()
-		if title == "" {
-			return fmt.Errorf("could not find stock title")
+	if stockSaveList != "" {
+		if len(symbols) == 0 {
+			return nil, fmt.Errorf("no symbols to save in watchlist %q", stockSaveList)
 		}
-
-		// Get price section
-		priceSection := doc.Find("section[data-testid='quote-price']").First()
-		if priceSection.Length() == 0 {
-			return fmt.Errorf("could not find price section")
+		lists[stockSaveList] = symbols
+		if err := lists.Save(path); err != nil {
+			return nil, err
 		}
+	}
+
+	return symbols, nil
+}
 
-		// Get price text and clean it up
-		priceText := priceSection.Text()
-		priceText = strings.TrimSpace(priceText)
-		priceText = strings.ReplaceAll(priceText, "  ", "\n")
+func watchlistsPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watchlists.yaml"), nil
+}
+
+// writeQuotes prints the successfully fetched quotes. A single quote is shown
+// in detail, several quotes as a table, a JSON array or CSV rows. With --stats
+// every quote is shown in detail followed by its key statistics.
+func writeQuotes(out io.Writer, results []stock.QuoteResult, multiple bool) error {
+	var quotes []*stock.Quote
+	for _, r := range results {
+		if r.Err == nil {
+			quotes = append(quotes, r.Quote)
+		}
+	}
 
-		// Output results
-		fmt.Printf("%s\n%s\n", title, priceText)
+	switch {
+	case stockJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if !multiple {
+			return encoder.Encode(quotes[0])
+		}
+		return encoder.Encode(quotes)
+	case stockCSV:
+		w := csv.NewWriter(out)
+		w.Write(stock.CSVHeader())
+		for _, q := range quotes {
+			w.Write(q.CSVRecord())
+		}
+		w.Flush()
+		return w.Error()
+	case stockStats:
+		for i, q := range quotes {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintln(out, q)
+			if q.Stats != nil {
+				fmt.Fprintf(out, "\n%s\n", q.Stats)
+			}
+		}
 		return nil
-	},
+	case !multiple:
+		fmt.Fprintln(out, quotes[0])
+		return nil
+	default:
+		_, err := io.WriteString(out, renderQuoteTable(results, nil, false))
+		return err
+	}
+}
+
+// quoteErrors reports symbols that could not be fetched
+func quoteErrors(errOut io.Writer, results []stock.QuoteResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(errOut, "Error fetching %s: %v\n", r.Symbol, r.Err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to fetch %d of %d symbols", failed, len(results))
+	}
+	return nil
+}
+
+// watchError is streamed by watch mode in place of a quote that could not be fetched
+type watchError struct {
+	Symbol string `json:"symbol"`
+	Error  string `json:"error"`
+}
+
+// watchQuotes refreshes the quotes every --watch interval until ctx is done.
+// Text output redraws the table in place on a terminal and appends a new
+// table otherwise; JSON and CSV output stream one record per quote and tick,
+// with an error field or column for symbols that could not be fetched.
+func watchQuotes(ctx context.Context, out io.Writer, client *stock.Client, symbols []string) error {
+	ticker := time.NewTicker(stockWatch)
+	defer ticker.Stop()
+
+	terminal := isTerminal(out)
+
+	previous := make(map[string]float64)
+	drawnLines := 0
+	var csvWriter *csv.Writer
+	header := append(stock.CSVHeader(), "error")
+	if stockCSV {
+		csvWriter = csv.NewWriter(out)
+		csvWriter.Write(header)
+	}
+
+	for {
+		results := client.GetQuotes(symbols, stockConcurrency)
+
+		switch {
+		case stockJSON:
+			encoder := json.NewEncoder(out)
+			for _, r := range results {
+				if r.Err != nil {
+					encoder.Encode(watchError{Symbol: r.Symbol, Error: r.Err.Error()})
+				} else {
+					encoder.Encode(r.Quote)
+				}
+			}
+		case stockCSV:
+			for _, r := range results {
+				if r.Err != nil {
+					record := make([]string, len(header))
+					record[0], record[len(record)-1] = r.Symbol, r.Err.Error()
+					csvWriter.Write(record)
+				} else {
+					csvWriter.Write(append(r.Quote.CSVRecord(), ""))
+				}
+			}
+			csvWriter.Flush()
+		default:
+			table := fmt.Sprintf("Updated %s, refreshing every %v (Ctrl-C to stop)\n\n", time.Now().Format("15:04:05"), stockWatch)
+			table += renderQuoteTable(results, previous, terminal)
+			switch {
+			case drawnLines > 0 && terminal:
+				// Move the cursor back to the top of the previous table and clear it
+				fmt.Fprintf(out, "\033[%dA\033[J", drawnLines)
+			case drawnLines > 0:
+				fmt.Fprintln(out)
+			}
+			io.WriteString(out, table)
+			drawnLines = countLines(table)
+		}
+
+		for _, r := range results {
+			if r.Err == nil {
+				previous[r.Symbol] = r.Quote.Price
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTerminal reports whether out is a terminal, which can take cursor movements and colors
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func init() {
 	rootCmd.AddCommand(stockCmd)
+	stockCmd.Flags().BoolVar(&stockJSON, "json", false, "Output quotes as JSON")
+	stockCmd.Flags().BoolVar(&stockCSV, "csv", false, "Output quotes as CSV")
+	stockCmd.Flags().BoolVar(&stockStats, "stats", false, "Show key statistics such as volume, market cap and P/E ratio")
+	stockCmd.Flags().StringVar(&stockList, "list", "", "Load symbols from the named watchlist")
+	stockCmd.Flags().StringVar(&stockSaveList, "save-list", "", "Save the symbols as the named watchlist")
+	stockCmd.Flags().DurationVar(&stockWatch, "watch", 0, "Refresh quotes at this interval, e.g. 30s")
+	// Persistent so that "stock alert run" polls with the same limit
+	stockCmd.PersistentFlags().IntVar(&stockConcurrency, "concurrency", 4, "Maximum number of quotes fetched at once")
 }
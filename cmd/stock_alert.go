@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/config"
+	"github.com/mjlefevre/sanoja/internal/notify"
+	"github.com/mjlefevre/sanoja/pkg/stock"
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertAbove     float64
+	alertBelow     float64
+	alertPctChange float64
+
+	alertInterval      time.Duration
+	alertWebhook       string
+	alertNotifyCommand string
+	alertOnce          bool
+)
+
+var stockAlertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Manage and run stock price alerts",
+	Long: `Manage stock price alerts and watch quotes for them.
+
+Alert rules are stored in alerts.yaml next to the config file
+(see "sanoja config path"). An alert fires once when its condition starts
+to hold, and again only after the condition stopped holding in between.
+Which conditions held is kept in alerts-state.yaml, so this also holds
+across runs, e.g. of "alert run --once" from cron.
+
+Examples:
+  sanoja stock alert add AAPL --above 250 --below 200
+  sanoja stock alert add NVDA --pct-change 5
+  sanoja stock alert list
+  sanoja stock alert remove 2
+  sanoja stock alert run --interval 5m
+  sanoja stock alert run --webhook https://example.com/hook
+  sanoja stock alert run --notify-command notify-send`,
+}
+
+var stockAlertAddCmd = &cobra.Command{
+	Use:   "add SYMBOL",
+	Short: "Add an alert rule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, rules, err := loadAlertRules()
+		if err != nil {
+			return err
+		}
+
+		rule, err := rules.Add(stock.AlertRule{
+			Symbol:    args[0],
+			Above:     alertAbove,
+			Below:     alertBelow,
+			PctChange: alertPctChange,
+		})
+		if err != nil {
+			return fmt.Errorf("%v; use --above, --below or --pct-change", err)
+		}
+		if err := rules.Save(path); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Added alert %d: %s %s\n", rule.ID, rule.Symbol, rule)
+		return nil
+	},
+}
+
+var stockAlertListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List alert rules",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, rules, err := loadAlertRules()
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No alert rules")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSYMBOL\tCONDITIONS")
+		for _, rule := range rules {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", rule.ID, rule.Symbol, rule)
+		}
+		return w.Flush()
+	},
+}
+
+var stockAlertRemoveCmd = &cobra.Command{
+	Use:   "remove ID...",
+	Short: "Remove alert rules",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, rules, err := loadAlertRules()
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid alert ID %q", arg)
+			}
+			if err := rules.Remove(id); err != nil {
+				return err
+			}
+		}
+		if err := rules.Save(path); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %d alert rule(s)\n", len(args))
+		return nil
+	},
+}
+
+var stockAlertRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Poll quotes and send notifications when alerts fire",
+	Long: `Poll the quotes of all symbols with alert rules every --interval and send a
+notification when an alert fires. Notifications are always printed; --webhook
+additionally POSTs them as JSON and --notify-command runs a program with the
+title and message as its last two arguments.
+
+The rules file is re-read on every poll, so rules can be added or removed
+while alerts are running.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+
+		sinks := []notify.Sink{notify.NewWriter(cmd.OutOrStdout())}
+		if alertWebhook != "" {
+			sinks = append(sinks, notify.NewWebhook(httpClient, alertWebhook))
+		}
+		if alertNotifyCommand != "" {
+			command, err := notify.NewCommand(alertNotifyCommand)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, command)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		client := stock.NewClient(stock.WithHTTPClient(httpClient))
+		return runAlerts(ctx, cmd.ErrOrStderr(), client, sinks)
+	},
+}
+
+// runAlerts checks the alert rules every --interval until ctx is done,
+// or once with --once
+func runAlerts(ctx context.Context, errOut io.Writer, client *stock.Client, sinks []notify.Sink) error {
+	ticker := time.NewTicker(alertInterval)
+	defer ticker.Stop()
+
+	statePath, err := alertStatePath()
+	if err != nil {
+		return err
+	}
+	monitor, err := stock.LoadAlertMonitor(statePath)
+	if err != nil {
+		return err
+	}
+	for {
+		_, rules, err := loadAlertRules()
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return fmt.Errorf("no alert rules; add one with \"sanoja stock alert add\"")
+		}
+
+		for _, r := range client.GetQuotes(rules.Symbols(), stockConcurrency) {
+			if r.Err != nil {
+				fmt.Fprintf(errOut, "Error fetching %s: %v\n", r.Symbol, r.Err)
+				continue
+			}
+			for _, alert := range monitor.Check(rules, r.Quote) {
+				n := notify.Notification{
+					Title:   "sanoja: " + alert.Quote.Symbol,
+					Message: alert.Message(),
+					Time:    time.Now(),
+					Data:    alert,
+				}
+				if err := notify.Send(ctx, sinks, n); err != nil {
+					fmt.Fprintf(errOut, "Error sending notification: %v\n", err)
+				}
+			}
+		}
+
+		if err := monitor.Save(statePath, rules); err != nil {
+			return err
+		}
+
+		if alertOnce {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func loadAlertRules() (string, stock.AlertRules, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, "alerts.yaml")
+	rules, err := stock.LoadAlertRules(path)
+	return path, rules, err
+}
+
+// alertStatePath returns the file recording which alert conditions held when
+// last checked, next to alerts.yaml
+func alertStatePath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "alerts-state.yaml"), nil
+}
+
+func init() {
+	stockCmd.AddCommand(stockAlertCmd)
+	stockAlertCmd.AddCommand(stockAlertAddCmd, stockAlertListCmd, stockAlertRemoveCmd, stockAlertRunCmd)
+
+	stockAlertAddCmd.Flags().Float64Var(&alertAbove, "above", 0, "Alert when the price rises above this value")
+	stockAlertAddCmd.Flags().Float64Var(&alertBelow, "below", 0, "Alert when the price falls below this value")
+	stockAlertAddCmd.Flags().Float64Var(&alertPctChange, "pct-change", 0, "Alert when the day's change reaches this many percent either way")
+
+	stockAlertRunCmd.Flags().DurationVar(&alertInterval, "interval", time.Minute, "How often to poll quotes")
+	stockAlertRunCmd.Flags().StringVar(&alertWebhook, "webhook", "", "POST notifications as JSON to this URL")
+	stockAlertRunCmd.Flags().StringVar(&alertNotifyCommand, "notify-command", "", "Run this command for each notification, e.g. notify-send")
+	stockAlertRunCmd.Flags().BoolVar(&alertOnce, "once", false, "Check the alerts once and exit")
+}
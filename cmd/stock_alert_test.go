@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStockAlert(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if _, err := runCommand(t, "", "stock", "alert", "add", "AAPL"); err == nil || !strings.Contains(err.Error(), "no conditions") {
+		t.Errorf("expected a rule without conditions to be rejected, got %v", err)
+	}
+
+	out, err := runCommand(t, "", "stock", "alert", "add", "aapl", "--above", "250", "--below", "200")
+	if err != nil {
+		t.Fatalf("alert add failed: %v\n%s", err, out)
+	}
+	if out != "Added alert 1: AAPL above 250, below 200\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if _, err := runCommand(t, "", "stock", "alert", "add", "AAPL", "--pct-change", "5"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = runCommand(t, "", "stock", "alert", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1   AAPL    above 250, below 200") || !strings.Contains(out, "2   AAPL    ±5% change") {
+		t.Errorf("unexpected list:\n%s", out)
+	}
+
+	// AAPL closed at 254.49 (+0.74%), so only the --above rule fires
+	out, err = runCommand(t, "stock", "stock", "alert", "run", "--once", "--concurrency", "1")
+	if err != nil {
+		t.Fatalf("alert run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "AAPL is above 250: 254.49 +1.88 (+0.74%)") || strings.Count(out, "\n") != 1 {
+		t.Errorf("unexpected notifications:\n%s", out)
+	}
+	if stockConcurrency != 1 {
+		t.Errorf("--concurrency = %d, want 1 for alert run", stockConcurrency)
+	}
+
+	// The alert already fired, so the next run stays quiet
+	out, err = runCommand(t, "stock", "stock", "alert", "run", "--once")
+	if err != nil {
+		t.Fatalf("alert run failed: %v\n%s", err, out)
+	}
+	if out != "" {
+		t.Errorf("expected no repeated notifications, got:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "", "stock", "alert", "remove", "1", "2"); err != nil {
+		t.Fatal(err)
+	}
+	out, _ = runCommand(t, "", "stock", "alert", "list")
+	if out != "No alert rules\n" {
+		t.Errorf("expected no rules after removal, got:\n%s", out)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mjlefevre/sanoja/pkg/stock"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyFrom     string
+	historyTo       string
+	historyInterval string
+	historyJSON     bool
+	historyOutput   string
+)
+
+var stockHistoryCmd = &cobra.Command{
+	Use:   "history SYMBOL",
+	Short: "Download historical stock prices from Yahoo Finance",
+	Long: `Download historical OHLCV (open, high, low, close, volume) prices from Yahoo Finance.
+
+Prices are written as CSV by default, with dividends and splits reported on
+the row of the day they occur. JSON output lists them separately.
+
+Examples:
+  sanoja stock history AAPL                                  # Daily prices for the last year
+  sanoja stock history AAPL --from 2024-01-01 --to 2024-12-31
+  sanoja stock history AAPL --interval 1wk --json
+  sanoja stock history NVDA --interval 1mo -o nvda.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to := time.Now().UTC().Truncate(24 * time.Hour)
+		if historyTo != "" {
+			t, err := time.Parse("2006-01-02", historyTo)
+			if err != nil {
+				return fmt.Errorf("invalid --to date %q: expected YYYY-MM-DD", historyTo)
+			}
+			to = t
+		}
+
+		from := to.AddDate(-1, 0, 0)
+		if historyFrom != "" {
+			t, err := time.Parse("2006-01-02", historyFrom)
+			if err != nil {
+				return fmt.Errorf("invalid --from date %q: expected YYYY-MM-DD", historyFrom)
+			}
+			from = t
+		}
+
+		if !stock.ValidInterval(historyInterval) {
+			return fmt.Errorf("invalid interval %q (expected one of %s)", historyInterval, strings.Join(stock.Intervals, ", "))
+		}
+
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+
+		// The end date is inclusive, so ask for everything before the next day
+		client := stock.NewClient(stock.WithHTTPClient(httpClient))
+		history, err := client.GetHistory(args[0], from, to.AddDate(0, 0, 1), historyInterval)
+		if err != nil {
+			return err
+		}
+
+		if historyOutput == "" {
+			return writeHistory(cmd.OutOrStdout(), history)
+		}
+
+		f, err := os.Create(historyOutput)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %v", err)
+		}
+		if err := writeHistory(f, history); err != nil {
+			f.Close()
+			return fmt.Errorf("error writing output file: %v", err)
+		}
+		// A failed close can mean the data never reached the file
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("error writing output file: %v", err)
+		}
+		return nil
+	},
+}
+
+func writeHistory(out io.Writer, history *stock.History) error {
+	if historyJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(history)
+	}
+
+	w := csv.NewWriter(out)
+	w.Write(stock.HistoryCSVHeader())
+	w.WriteAll(history.CSVRecords())
+	return w.Error()
+}
+
+func init() {
+	stockCmd.AddCommand(stockHistoryCmd)
+	stockHistoryCmd.Flags().StringVar(&historyFrom, "from", "", "Start date, YYYY-MM-DD (default one year before --to)")
+	stockHistoryCmd.Flags().StringVar(&historyTo, "to", "", "End date, inclusive, YYYY-MM-DD (default today)")
+	stockHistoryCmd.Flags().StringVar(&historyInterval, "interval", "1d", "Price interval: "+strings.Join(stock.Intervals, ", "))
+	stockHistoryCmd.Flags().BoolVar(&historyJSON, "json", false, "Output history as JSON instead of CSV")
+	stockHistoryCmd.Flags().StringVarP(&historyOutput, "output", "o", "", "Write to FILE instead of standard output")
+}
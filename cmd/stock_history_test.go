@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjlefevre/sanoja/pkg/stock"
+)
+
+func TestStockHistoryCSV(t *testing.T) {
+	out, err := runCommand(t, "stock_history", "stock", "history", "NVDA", "--from", "2024-06-05", "--to", "2024-06-12")
+	if err != nil {
+		t.Fatalf("stock history failed: %v\n%s", err, out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected header and 6 rows, got:\n%s", out)
+	}
+	if lines[0] != "date,open,high,low,close,adj_close,volume,dividend,split" {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[4] != "2024-06-10,120.37,123.1,117.01,121.79,121.77,314162700,,10:1" {
+		t.Errorf("unexpected split row: %s", lines[4])
+	}
+}
+
+func TestStockHistoryJSON(t *testing.T) {
+	out, err := runCommand(t, "stock_history", "stock", "history", "NVDA", "--from", "2024-06-05", "--to", "2024-06-12", "--json")
+	if err != nil {
+		t.Fatalf("stock history failed: %v\n%s", err, out)
+	}
+
+	var history stock.History
+	if err := json.Unmarshal([]byte(out), &history); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(history.Bars) != 6 || len(history.Splits) != 1 || len(history.Dividends) != 1 {
+		t.Errorf("unexpected history: %d bars, %d splits, %d dividends", len(history.Bars), len(history.Splits), len(history.Dividends))
+	}
+}
+
+func TestStockHistoryOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nvda.csv")
+	out, err := runCommand(t, "stock_history", "stock", "history", "NVDA", "--from", "2024-06-05", "--to", "2024-06-12", "-o", path)
+	if err != nil {
+		t.Fatalf("stock history failed: %v\n%s", err, out)
+	}
+	if out != "" {
+		t.Errorf("expected nothing on standard output, got:\n%s", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 7 {
+		t.Errorf("expected header and 6 rows in the file, got:\n%s", data)
+	}
+
+	// Writes to /dev/full fail with ENOSPC
+	if _, err := os.Stat("/dev/full"); err != nil {
+		return
+	}
+	_, err = runCommand(t, "stock_history", "stock", "history", "NVDA", "--from", "2024-06-05", "--to", "2024-06-12", "-o", "/dev/full")
+	if err == nil || !strings.Contains(err.Error(), "error writing output file") {
+		t.Errorf("expected the write error to be returned, got %v", err)
+	}
+}
+
+func TestStockHistoryInvalidDate(t *testing.T) {
+	_, err := runCommand(t, "", "stock", "history", "NVDA", "--from", "June 5")
+	if err == nil || !strings.Contains(err.Error(), "invalid --from date") {
+		t.Errorf("expected invalid date error, got %v", err)
+	}
+}
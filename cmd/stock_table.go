@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mjlefevre/sanoja/pkg/stock"
+)
+
+const (
+	ansiReset = "\033[0m"
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+)
+
+// renderQuoteTable formats quotes as an aligned table. If previous holds the
+// prices of the last refresh, a final column shows the movement since then,
+// colored when color is set.
+func renderQuoteTable(results []stock.QuoteResult, previous map[string]float64, color bool) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	header := "SYMBOL\tNAME\tPRICE\tCHANGE\tCHANGE %\tMARKET"
+	if previous != nil {
+		header += "\tMOVE"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%s\terror: %v\n", r.Symbol, r.Err)
+			continue
+		}
+
+		q := r.Quote
+		row := fmt.Sprintf("%s\t%s\t%.2f\t%+.2f\t%+.2f%%\t%s",
+			q.Symbol, truncate(q.Name, 24), q.Price, q.Change, q.ChangePercent, strings.ToLower(q.MarketState))
+		if previous != nil {
+			// The movement column is last so its color codes do not upset alignment
+			row += "\t" + movement(q.Price, previous[q.Symbol], color)
+		}
+		fmt.Fprintln(w, row)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// movement describes the price change since the previous refresh
+func movement(price, last float64, color bool) string {
+	if last == 0 {
+		return ""
+	}
+
+	delta := price - last
+	var text, code string
+	switch {
+	case delta > 0:
+		text, code = fmt.Sprintf("▲ %+.2f", delta), ansiGreen
+	case delta < 0:
+		text, code = fmt.Sprintf("▼ %+.2f", delta), ansiRed
+	default:
+		return "="
+	}
+
+	if !color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func countLines(s string) int {
+	return strings.Count(s, "\n")
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/cassette"
+	"github.com/mjlefevre/sanoja/pkg/stock"
+)
+
+func TestStock(t *testing.T) {
+	out, err := runCommand(t, "stock", "stock", "aapl")
+	if err != nil {
+		t.Fatalf("stock failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "Apple Inc. (AAPL)\n") {
+		t.Errorf("expected the company name first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "254.49") {
+		t.Errorf("expected the price in the output, got:\n%s", out)
+	}
+}
+
+func TestStockNotRecorded(t *testing.T) {
+	_, err := runCommand(t, "stock", "stock", "MSFT")
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("expected replay miss, got %v", err)
+	}
+}
+
+func TestStockJSON(t *testing.T) {
+	out, err := runCommand(t, "stock", "stock", "AAPL", "--json")
+	if err != nil {
+		t.Fatalf("stock failed: %v\n%s", err, out)
+	}
+
+	var quote stock.Quote
+	if err := json.Unmarshal([]byte(out), &quote); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if quote.Symbol != "AAPL" || quote.Price != 254.49 || quote.Currency != "USD" {
+		t.Errorf("unexpected quote: %+v", quote)
+	}
+	if quote.Stats == nil || quote.Stats.MarketCap != 3.847e12 || quote.Stats.PERatio != 41.86 {
+		t.Errorf("unexpected statistics: %+v", quote.Stats)
+	}
+}
+
+func TestStockStats(t *testing.T) {
+	out, err := runCommand(t, "stock", "stock", "AAPL", "--stats")
+	if err != nil {
+		t.Fatalf("stock failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Market Cap           3.85T", "P/E Ratio            41.86", "Earnings Date        2025-01-30 - 2025-02-03"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestStockCSV(t *testing.T) {
+	out, err := runCommand(t, "stock", "stock", "AAPL", "--csv")
+	if err != nil {
+		t.Fatalf("stock failed: %v\n%s", err, out)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV output: %v\n%s", err, out)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected header and one row, got %d rows", len(records))
+	}
+	if records[1][0] != "AAPL" || records[1][4] != "254.49" {
+		t.Errorf("unexpected row: %v", records[1])
+	}
+}
+
+func TestStockMultipleSymbols(t *testing.T) {
+	out, err := runCommand(t, "stocks", "stock", "aapl", "NOK", "AAPL")
+	if err != nil {
+		t.Fatalf("stock failed: %v\n%s", err, out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[0], "SYMBOL") || !strings.HasPrefix(lines[1], "AAPL") || !strings.HasPrefix(lines[2], "NOK") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+	if !strings.Contains(lines[2], "-1.11%") || !strings.Contains(lines[2], "pre") {
+		t.Errorf("unexpected NOK row: %s", lines[2])
+	}
+}
+
+func TestStockPartialFailure(t *testing.T) {
+	out, err := runCommand(t, "stocks", "stock", "AAPL", "NOPE", "--json")
+	if err == nil || !strings.Contains(err.Error(), "failed to fetch 1 of 2 symbols") {
+		t.Errorf("expected partial failure, got %v", err)
+	}
+	if !strings.Contains(out, "Error fetching NOPE: no quote found for symbol NOPE") {
+		t.Errorf("missing error report in:\n%s", out)
+	}
+}
+
+func TestStockWatchlist(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if out, err := runCommand(t, "stocks", "stock", "nok", "aapl", "--save-list", "mine"); err != nil {
+		t.Fatalf("stock --save-list failed: %v\n%s", err, out)
+	}
+
+	out, err := runCommand(t, "stocks", "stock", "--list", "mine", "--csv")
+	if err != nil {
+		t.Fatalf("stock --list failed: %v\n%s", err, out)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1][0] != "NOK" || records[2][0] != "AAPL" {
+		t.Errorf("unexpected watchlist quotes: %v", records)
+	}
+
+	if _, err := runCommand(t, "stocks", "stock", "--list", "other"); err == nil || !strings.Contains(err.Error(), `unknown watchlist "other"`) {
+		t.Errorf("expected unknown watchlist error, got %v", err)
+	}
+}
+
+func TestRenderQuoteTableMovement(t *testing.T) {
+	results := []stock.QuoteResult{
+		{Symbol: "AAPL", Quote: &stock.Quote{Symbol: "AAPL", Name: "Apple Inc.", Price: 101}},
+		{Symbol: "NOK", Quote: &stock.Quote{Symbol: "NOK", Name: "Nokia Oyj", Price: 4}},
+	}
+	previous := map[string]float64{"AAPL": 100, "NOK": 4.5}
+
+	table := renderQuoteTable(results, previous, false)
+	if !strings.Contains(table, "▲ +1.00") || !strings.Contains(table, "▼ -0.50") {
+		t.Errorf("missing movement markers:\n%s", table)
+	}
+
+	colored := renderQuoteTable(results, previous, true)
+	if !strings.Contains(colored, ansiGreen+"▲ +1.00"+ansiReset) {
+		t.Errorf("expected colored movement:\n%q", colored)
+	}
+}
+
+func TestStockWatchPlainOutput(t *testing.T) {
+	recorder, err := cassette.New(filepath.Join("testdata", "cassettes", "stocks"), cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := stock.NewClient(stock.WithHTTPClient(&http.Client{Transport: recorder}))
+
+	resetFlags(rootCmd)
+	stockWatch = 10 * time.Millisecond
+	defer func() { stockWatch = 0 }()
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := watchQuotes(ctx, &out, client, []string{"AAPL", "NOK"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "Updated ") < 2 {
+		t.Fatalf("expected several refreshes, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "\033") {
+		t.Errorf("expected no terminal escape codes when not writing to a terminal, got %q", out.String())
+	}
+}
+
+func TestStockWatchErrors(t *testing.T) {
+	recorder, err := cassette.New(filepath.Join("testdata", "cassettes", "stocks"), cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := stock.NewClient(stock.WithHTTPClient(&http.Client{Transport: recorder}))
+
+	watch := func(format *bool) string {
+		resetFlags(rootCmd)
+		*format = true
+		stockWatch = time.Hour
+		defer func() { *format, stockWatch = false, 0 }()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var out bytes.Buffer
+		if err := watchQuotes(ctx, &out, client, []string{"AAPL", "NOPE"}); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(watch(&stockJSON)))
+	var records []map[string]any
+	for decoder.More() {
+		var record map[string]any
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 || records[0]["symbol"] != "AAPL" || records[0]["error"] != nil ||
+		records[1]["symbol"] != "NOPE" || records[1]["error"] == "" || records[1]["error"] == nil {
+		t.Errorf("expected a quote and an error record, got %v", records)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(watch(&stockCSV))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := len(stock.CSVHeader())
+	if len(rows) != 3 || rows[0][last] != "error" || rows[1][0] != "AAPL" || rows[1][last] != "" ||
+		rows[2][0] != "NOPE" || rows[2][last] == "" {
+		t.Errorf("expected an error column, got %v", rows)
+	}
+}
@@ -2,41 +2,61 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
-
-	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
 var (
-	count int
+	count         int
+	uuidVersion   int
+	uuidNamespace string
+	uuidName      string
 )
 
 // uuidCmd represents the uuid command
 var uuidCmd = &cobra.Command{
 	Use:   "uuid",
 	Short: "Generate one or more UUIDs",
-	Long: `Generate one or more random UUIDs (v4).
+	Long: `Generate one or more UUIDs.
+
+Versions:
+  1  time-based with the MAC address of this machine
+  3  name-based using MD5, from --namespace and --name
+  4  random (default)
+  5  name-based using SHA-1, from --namespace and --name
+  6  time-based like v1 but sortable by time
+  7  time-ordered using the Unix time in milliseconds, ideal for database keys
+
+Bulk v7 output is monotonic: UUIDs generated within the same millisecond
+still sort in the order they were generated.
 
 Examples:
-  sanoja uuid           # Generate one UUID
-  sanoja uuid -n 5      # Generate 5 UUIDs`,
+  sanoja uuid                                  # Generate one UUID
+  sanoja uuid -n 5                             # Generate 5 UUIDs
+  sanoja uuid --version 7 -n 1000              # Generate 1000 time-ordered UUIDs
+  sanoja uuid --version 5 --name example.com   # Name-based UUID in the DNS namespace
+  sanoja uuid -v 3 --namespace url --name https://example.com/
+  sanoja uuid -v 5 --namespace 6ba7b810-9dad-11d1-80b4-00c04fd430c8 --name thing
+
+Use "sanoja uuid parse" to inspect existing UUIDs and "sanoja uuid convert"
+to change their encoding.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if count < 1 {
-			return fmt.Errorf("number of UUIDs must be positive, got %d", count)
-		}
+		return runID(cmd, "uuid")
+	},
+}
 
-		var uuids []string
-		for i := 0; i < count; i++ {
-			id := uuid.New()
-			uuids = append(uuids, id.String())
+// checkMonotonic verifies that IDs sort in the order they were generated
+func checkMonotonic(ids []string) error {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return fmt.Errorf("IDs out of order: %s generated after %s", ids[i], ids[i-1])
 		}
-		fmt.Println(strings.Join(uuids, "\n"))
-		return nil
-	},
+	}
+	return nil
 }
 
 func init() {
 	rootCmd.AddCommand(uuidCmd)
 	uuidCmd.Flags().IntVarP(&count, "number", "n", 1, "Number of UUIDs to generate")
+	addUUIDFlags(uuidCmd)
 }
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mjlefevre/sanoja/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uuidParseJSON bool
+	uuidConvertTo string
+)
+
+// uuidFormats are the encodings accepted by "uuid convert --to"
+var uuidFormats = []string{"canonical", "upper", "braces", "urn", "hex", "base64", "base32", "raw"}
+
+// maxUUID is the all-ones UUID of RFC 9562, the counterpart of uuid.Nil
+var maxUUID = uuid.Must(uuid.Parse("ffffffff-ffff-ffff-ffff-ffffffffffff"))
+
+// uuidBase32 encodes UUIDs as 26 unpadded characters
+var uuidBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var uuidParseCmd = &cobra.Command{
+	Use:   "parse [ID...]",
+	Short: "Validate UUIDs and show what they contain",
+	Long: `Validate UUIDs and show their version, variant and, depending on the
+version, the embedded timestamp (v1, v6, v7), clock sequence and node (v1, v6).
+
+IDs are read from the arguments, or one or more per line from stdin if there
+are none. Any encoding written by "sanoja uuid convert" is accepted, except raw.
+
+Examples:
+  sanoja uuid parse 017f22e2-79b0-7cc3-98c4-dc0c0c07398f
+  sanoja uuid parse {C232AB00-9414-11EC-B3C8-9F6BDECED846}
+  grep -o 'request_id=[^ ]*' app.log | cut -d= -f2 | sanoja uuid parse --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var infos []uuidInfo
+		err := eachUUID(cmd, args, func(u uuid.UUID) error {
+			infos = append(infos, inspectUUID(u))
+			return nil
+		})
+		if len(infos) == 0 {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if uuidParseJSON {
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(infos); err != nil {
+				return err
+			}
+		} else {
+			for i, info := range infos {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				writeUUIDInfo(out, info)
+			}
+		}
+		// Valid IDs are reported even if others were invalid
+		return err
+	},
+}
+
+var uuidConvertCmd = &cobra.Command{
+	Use:   "convert [ID...]",
+	Short: "Convert UUIDs between encodings",
+	Long: `Convert UUIDs between encodings.
+
+Formats:
+  canonical  01234567-89ab-cdef-0123-456789abcdef (default)
+  upper      01234567-89AB-CDEF-0123-456789ABCDEF
+  braces     {01234567-89ab-cdef-0123-456789abcdef}
+  urn        urn:uuid:01234567-89ab-cdef-0123-456789abcdef
+  hex        0123456789abcdef0123456789abcdef
+  base64     URL-safe base64 without padding, 22 characters
+  base32     RFC 4648 base32 without padding, 26 characters
+  raw        the 16 bytes of each UUID, without separators
+
+IDs are read from the arguments, or one or more per line from stdin if there
+are none, in any of the formats above except raw.
+
+Examples:
+  sanoja uuid convert --to urn 017f22e2-79b0-7cc3-98c4-dc0c0c07398f
+  sanoja uuid -n 10 | sanoja uuid convert --to base64
+  sanoja uuid convert --to raw $ID > id.bin`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(uuidConvertTo)
+		if !isUUIDFormat(format) {
+			return fmt.Errorf("unknown format %q (expected %s)", uuidConvertTo, strings.Join(uuidFormats, ", "))
+		}
+
+		out := cmd.OutOrStdout()
+		return eachUUID(cmd, args, func(u uuid.UUID) error {
+			if format == "raw" {
+				_, err := out.Write(u[:])
+				return err
+			}
+			_, err := fmt.Fprintln(out, formatUUID(u, format))
+			return err
+		})
+	},
+}
+
+// uuidInfo is what "uuid parse" reports about a UUID
+type uuidInfo struct {
+	UUID          string     `json:"uuid"`
+	Version       int        `json:"version"`
+	Description   string     `json:"description"`
+	Variant       string     `json:"variant"`
+	Time          *time.Time `json:"time,omitempty"`
+	ClockSequence *int       `json:"clockSequence,omitempty"`
+	Node          string     `json:"node,omitempty"`
+}
+
+// uuidVersions describes the versions defined by RFC 9562
+var uuidVersions = map[int]string{
+	1: "time-based",
+	2: "DCE security",
+	3: "name-based, MD5",
+	4: "random",
+	5: "name-based, SHA-1",
+	6: "reordered time-based",
+	7: "Unix time-based",
+	8: "custom",
+}
+
+func inspectUUID(u uuid.UUID) uuidInfo {
+	info := uuidInfo{
+		UUID:    u.String(),
+		Version: int(u.Version()),
+		Variant: variantName(u),
+	}
+
+	switch u {
+	case uuid.Nil:
+		info.Description = "nil UUID"
+		return info
+	case maxUUID:
+		info.Description = "max UUID"
+		return info
+	}
+
+	info.Description = uuidVersions[info.Version]
+	if info.Description == "" {
+		info.Description = "unknown version"
+	}
+	// The layout of the remaining fields is only defined for the RFC variant
+	if u.Variant() != uuid.RFC4122 {
+		return info
+	}
+
+	if t, ok := id.UUIDTime(u); ok {
+		info.Time = &t
+	}
+	if info.Version == 1 || info.Version == 6 {
+		seq := u.ClockSequence()
+		info.ClockSequence = &seq
+		info.Node = formatNode(u.NodeID())
+	}
+	return info
+}
+
+func variantName(u uuid.UUID) string {
+	switch u.Variant() {
+	case uuid.RFC4122:
+		return "RFC 9562"
+	case uuid.Microsoft:
+		return "Microsoft"
+	case uuid.Future:
+		return "future"
+	default:
+		return "NCS"
+	}
+}
+
+func formatNode(node []byte) string {
+	parts := make([]string, len(node))
+	for i, b := range node {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func writeUUIDInfo(out io.Writer, info uuidInfo) {
+	fmt.Fprintf(out, "UUID:       %s\n", info.UUID)
+	fmt.Fprintf(out, "Version:    %d (%s)\n", info.Version, info.Description)
+	fmt.Fprintf(out, "Variant:    %s\n", info.Variant)
+	if info.Time != nil {
+		fmt.Fprintf(out, "Time:       %s\n", info.Time.Format(time.RFC3339Nano))
+	}
+	if info.ClockSequence != nil {
+		fmt.Fprintf(out, "Clock seq:  %d\n", *info.ClockSequence)
+	}
+	if info.Node != "" {
+		fmt.Fprintf(out, "Node:       %s\n", info.Node)
+	}
+}
+
+func isUUIDFormat(format string) bool {
+	for _, f := range uuidFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func formatUUID(u uuid.UUID, format string) string {
+	switch format {
+	case "upper":
+		return strings.ToUpper(u.String())
+	case "braces":
+		return "{" + u.String() + "}"
+	case "urn":
+		return u.URN()
+	case "hex":
+		return hex.EncodeToString(u[:])
+	case "base64":
+		return base64.RawURLEncoding.EncodeToString(u[:])
+	case "base32":
+		return uuidBase32.EncodeToString(u[:])
+	default:
+		return u.String()
+	}
+}
+
+// decodeUUID parses a UUID in any of the text encodings of "uuid convert"
+func decodeUUID(s string) (uuid.UUID, error) {
+	u, err := uuid.Parse(s)
+	if err == nil {
+		return u, nil
+	}
+
+	var data []byte
+	switch len(s) {
+	case 22:
+		data, _ = base64.RawURLEncoding.DecodeString(s)
+		if data == nil {
+			data, _ = base64.RawStdEncoding.DecodeString(s)
+		}
+	case 24:
+		data, _ = base64.StdEncoding.DecodeString(s)
+	case 26:
+		data, _ = uuidBase32.DecodeString(strings.ToUpper(s))
+	}
+	if len(data) == 16 {
+		return uuid.UUID(data), nil
+	}
+	return uuid.Nil, fmt.Errorf("invalid UUID %q: %v", s, err)
+}
+
+// eachUUID calls fn for every UUID in args, or on stdin if args is empty.
+// Invalid IDs are reported on stderr without stopping the rest.
+func eachUUID(cmd *cobra.Command, args []string, fn func(uuid.UUID) error) error {
+	inputs := args
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			inputs = append(inputs, strings.Fields(scanner.Text())...)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("error reading stdin: %v", err)
+		}
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no UUIDs given")
+	}
+
+	invalid := 0
+	for _, input := range inputs {
+		u, err := decodeUUID(input)
+		if err != nil {
+			invalid++
+			fmt.Fprintln(cmd.ErrOrStderr(), err)
+			continue
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d UUIDs are invalid", invalid, len(inputs))
+	}
+	return nil
+}
+
+func init() {
+	uuidCmd.AddCommand(uuidParseCmd)
+	uuidCmd.AddCommand(uuidConvertCmd)
+	uuidParseCmd.Flags().BoolVar(&uuidParseJSON, "json", false, "Output as JSON")
+	uuidConvertCmd.Flags().StringVarP(&uuidConvertTo, "to", "t", "canonical", "Output format: "+strings.Join(uuidFormats, ", "))
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUUIDVersions(t *testing.T) {
+	for _, version := range []string{"1", "4", "6", "7"} {
+		out, err := runCommand(t, "", "uuid", "--version", version)
+		if err != nil {
+			t.Fatalf("uuid --version %s: %v", version, err)
+		}
+		id, err := uuid.Parse(strings.TrimSpace(out))
+		if err != nil {
+			t.Fatalf("uuid --version %s printed %q: %v", version, out, err)
+		}
+		if got := id.Version().String(); got != "VERSION_"+version {
+			t.Errorf("uuid --version %s generated %s", version, got)
+		}
+	}
+}
+
+// TestUUIDv6Layout checks the v6 timestamp layout of RFC 9562: the 60-bit
+// timestamp split most significant bits first over time_high, time_mid and
+// time_low, so that v6 UUIDs sort in the order they were generated
+func TestUUIDv6Layout(t *testing.T) {
+	start := time.Now()
+	out, err := runCommand(t, "", "uuid", "--version", "6", "-n", "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(out)
+	if len(lines) != 100 {
+		t.Fatalf("expected 100 UUIDs, got %d", len(lines))
+	}
+	if !sort.StringsAreSorted(lines) {
+		t.Errorf("v6 UUIDs should sort in generation order:\n%s", out)
+	}
+
+	u := uuid.MustParse(lines[0])
+	ticks := int64(u[0])<<52 | int64(u[1])<<44 | int64(u[2])<<36 | int64(u[3])<<28 |
+		int64(u[4])<<20 | int64(u[5])<<12 | int64(u[6]&0x0f)<<8 | int64(u[7])
+	// The ticks are 100ns intervals since 1582-10-15, 0x01B21DD213814000 of
+	// them before the Unix epoch
+	ts := time.Unix(0, (ticks-0x01B21DD213814000)*100)
+	if ts.Before(start.Add(-time.Second)) || ts.After(time.Now().Add(time.Second)) {
+		t.Errorf("v6 UUID %s has timestamp %v, want about %v", u, ts, start)
+	}
+}
+
+func TestUUIDNameBased(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--version", "5", "--name", "example.com"}, "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+		{[]string{"--version", "5", "--namespace", "DNS", "--name", "example.com"}, "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+		{[]string{"--version", "3", "--namespace", "url", "--name", "https://example.com/"}, "b9dcdff8-af4a-365d-8043-0f8361942709"},
+		{[]string{"--version", "5", "--namespace", uuid.NameSpaceDNS.String(), "--name", "example.com"}, "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+	}
+	for _, tt := range tests {
+		out, err := runCommand(t, "", append([]string{"uuid"}, tt.args...)...)
+		if err != nil {
+			t.Fatalf("uuid %v: %v", tt.args, err)
+		}
+		if got := strings.TrimSpace(out); got != tt.want {
+			t.Errorf("uuid %v = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestUUIDErrors(t *testing.T) {
+	tests := [][]string{
+		{"--version", "5"},
+		{"--version", "3", "--name", "x", "-n", "2"},
+		{"--version", "5", "--namespace", "nope", "--name", "x"},
+		{"--version", "4", "--name", "x"},
+		{"--version", "2"},
+		{"-n", "0"},
+	}
+	for _, args := range tests {
+		if _, err := runCommand(t, "", append([]string{"uuid"}, args...)...); err == nil {
+			t.Errorf("uuid %v: expected an error", args)
+		}
+	}
+}
+
+func TestUUIDv7Monotonic(t *testing.T) {
+	out, err := runCommand(t, "", "uuid", "--version", "7", "-n", "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := strings.Fields(out)
+	if len(ids) != 1000 {
+		t.Fatalf("got %d UUIDs, want 1000", len(ids))
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("v7 UUIDs are not in generation order")
+	}
+
+	// A thousand UUIDs take well under a millisecond each, so many share a
+	// timestamp and their order must come from the sub-millisecond counter
+	sameMillisecond := 0
+	seen := make(map[string]bool)
+	for i, id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate UUID %s", id)
+		}
+		seen[id] = true
+		if i > 0 && id[:13] == ids[i-1][:13] {
+			sameMillisecond++
+		}
+	}
+	if sameMillisecond == 0 {
+		t.Error("expected some UUIDs to share a millisecond")
+	}
+}
+
+func TestUUIDParse(t *testing.T) {
+	// Test vectors from RFC 9562 appendix A, all for 2022-02-22 19:22:22 UTC
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"C232AB00-9414-11EC-B3C8-9F6BDECED846", []string{
+			"Version:    1 (time-based)",
+			"Time:       2022-02-22T19:22:22Z",
+			"Clock seq:  13256",
+			"Node:       9f:6b:de:ce:d8:46",
+		}},
+		{"1EC9414C-232A-6B00-B3C8-9F6BDECED846", []string{
+			"Version:    6 (reordered time-based)",
+			"Time:       2022-02-22T19:22:22Z",
+			"Clock seq:  13256",
+		}},
+		{"urn:uuid:017f22e2-79b0-7cc3-98c4-dc0c0c07398f", []string{
+			"UUID:       017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+			"Version:    7 (Unix time-based)",
+			"Variant:    RFC 9562",
+			"Time:       2022-02-22T19:22:22Z",
+		}},
+		{"{919108f7-52d1-4320-9bac-f847db4148a8}", []string{
+			"Version:    4 (random)",
+		}},
+	}
+	for _, tt := range tests {
+		out, err := runCommand(t, "", "uuid", "parse", tt.id)
+		if err != nil {
+			t.Fatalf("uuid parse %s: %v", tt.id, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out, want+"\n") {
+				t.Errorf("uuid parse %s: missing %q in:\n%s", tt.id, want, out)
+			}
+		}
+	}
+
+	out, err := runCommand(t, "", "uuid", "parse", "919108f7-52d1-4320-9bac-f847db4148a8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "Time:") || strings.Contains(out, "Node:") {
+		t.Errorf("v4 UUID should have no time or node:\n%s", out)
+	}
+}
+
+func TestUUIDParseJSON(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader("017f22e2-79b0-7cc3-98c4-dc0c0c07398f not-a-uuid\n00000000-0000-0000-0000-000000000000\n"))
+	defer rootCmd.SetIn(nil)
+
+	out, err := runCommand(t, "", "uuid", "parse", "--json")
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 UUIDs are invalid") {
+		t.Errorf("expected an error for the invalid UUID, got %v", err)
+	}
+	if !strings.Contains(out, `invalid UUID "not-a-uuid"`) {
+		t.Errorf("invalid UUID not reported:\n%s", out)
+	}
+
+	// The JSON is followed by the error and usage
+	var infos []uuidInfo
+	if err := json.NewDecoder(strings.NewReader(out[strings.Index(out, "[\n"):])).Decode(&infos); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("got %d UUIDs, want 2", len(infos))
+	}
+	if infos[0].Version != 7 || infos[0].Time == nil || !infos[0].Time.Equal(time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)) {
+		t.Errorf("unexpected v7 info: %+v", infos[0])
+	}
+	if infos[1].Description != "nil UUID" {
+		t.Errorf("description = %q, want nil UUID", infos[1].Description)
+	}
+}
+
+func TestUUIDConvert(t *testing.T) {
+	const id = "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+	tests := map[string]string{
+		"canonical": id,
+		"upper":     "017F22E2-79B0-7CC3-98C4-DC0C0C07398F",
+		"braces":    "{" + id + "}",
+		"urn":       "urn:uuid:" + id,
+		"hex":       "017f22e279b07cc398c4dc0c0c07398f",
+		"base64":    "AX8i4nmwfMOYxNwMDAc5jw",
+		"base32":    "AF7SFYTZWB6MHGGE3QGAYBZZR4",
+	}
+	for format, want := range tests {
+		out, err := runCommand(t, "", "uuid", "convert", "--to", format, id)
+		if err != nil {
+			t.Fatalf("uuid convert --to %s: %v", format, err)
+		}
+		if got := strings.TrimSpace(out); got != want {
+			t.Errorf("uuid convert --to %s = %s, want %s", format, got, want)
+		}
+
+		// Every format except raw is accepted back as input
+		out, err = runCommand(t, "", "uuid", "convert", want)
+		if err != nil {
+			t.Fatalf("uuid convert %s: %v", want, err)
+		}
+		if got := strings.TrimSpace(out); got != id {
+			t.Errorf("uuid convert %s = %s, want %s", want, got, id)
+		}
+	}
+}
+
+func TestUUIDConvertRaw(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader("017f22e2-79b0-7cc3-98c4-dc0c0c07398f\nC232AB00-9414-11EC-B3C8-9F6BDECED846\n"))
+	defer rootCmd.SetIn(nil)
+
+	out, err := runCommand(t, "", "uuid", "convert", "--to", "raw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 32 {
+		t.Fatalf("got %d bytes, want 32", len(out))
+	}
+	if got := uuid.UUID([]byte(out[16:])).String(); got != "c232ab00-9414-11ec-b3c8-9f6bdeced846" {
+		t.Errorf("second UUID = %s", got)
+	}
+
+	if _, err := runCommand(t, "", "uuid", "convert", "--to", "ascii85", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
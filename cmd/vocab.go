@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mjlefevre/sanoja/internal/config"
+	"github.com/mjlefevre/sanoja/pkg/words"
+	"github.com/spf13/cobra"
+)
+
+var vocabLang string
+
+var vocabCmd = &cobra.Command{
+	Use:   "vocab",
+	Short: "Manage the words you already know",
+	Long: `Manage your known words, per language. "sanoja words --unknown" leaves
+them out, so that only the words you still have to learn are listed.
+
+Known words are stored in vocab.yaml next to the config file
+(see "sanoja config path"). English words are matched on their stems, so
+knowing "run" also covers "runs" and "running". Words in other languages
+are not stemmed, so add each form you know.
+
+Examples:
+  sanoja vocab add house tree run
+  sanoja vocab add --lang fi sauna löyly
+  sanoja vocab remove tree
+  sanoja vocab import words.txt
+  sanoja vocab import deck.csv --lang fi
+  sanoja vocab export > words.txt`,
+}
+
+var vocabAddCmd = &cobra.Command{
+	Use:   "add WORD...",
+	Short: "Add known words",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateKnownWords(cmd.OutOrStdout(), func(known words.KnownWords) (int, int) {
+			return known.Add(vocabLang, args...), len(args)
+		}, "Added %d of %d words\n")
+	},
+}
+
+var vocabRemoveCmd = &cobra.Command{
+	Use:   "remove WORD...",
+	Short: "Remove known words",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateKnownWords(cmd.OutOrStdout(), func(known words.KnownWords) (int, int) {
+			return known.Remove(vocabLang, args...), len(args)
+		}, "Removed %d of %d words\n")
+	},
+}
+
+var vocabImportCmd = &cobra.Command{
+	Use:   "import FILE|-",
+	Short: "Add known words from a list with one word per line",
+	Long: `Add known words from a file, or standard input with "-". Each line holds
+one word; only the first field of CSV or tab-separated lines is used, so
+flashcard decks exported from Anki can be imported as they are. Blank lines
+and lines starting with # are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in := cmd.InOrStdin()
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("error opening word list: %v", err)
+			}
+			defer f.Close()
+			in = f
+		}
+		list, err := words.ReadWordList(in)
+		if err != nil {
+			return err
+		}
+
+		return updateKnownWords(cmd.OutOrStdout(), func(known words.KnownWords) (int, int) {
+			return known.Add(vocabLang, list...), len(list)
+		}, "Imported %d new of %d words\n")
+	},
+}
+
+var vocabExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the known words, one per line",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, known, err := loadKnownWords()
+		if err != nil {
+			return err
+		}
+		for _, word := range known.Words(vocabLang) {
+			fmt.Fprintln(cmd.OutOrStdout(), word)
+		}
+		return nil
+	},
+}
+
+// updateKnownWords applies a change to the known words, saves them and
+// reports how many of the given words the change affected
+func updateKnownWords(out io.Writer, update func(words.KnownWords) (int, int), format string) error {
+	path, known, err := loadKnownWords()
+	if err != nil {
+		return err
+	}
+	changed, total := update(known)
+	if err := known.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, format, changed, total)
+	return nil
+}
+
+func loadKnownWords() (string, words.KnownWords, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, "vocab.yaml")
+	known, err := words.LoadKnownWords(path)
+	return path, known, err
+}
+
+func init() {
+	rootCmd.AddCommand(vocabCmd)
+	vocabCmd.AddCommand(vocabAddCmd, vocabRemoveCmd, vocabImportCmd, vocabExportCmd)
+	vocabCmd.PersistentFlags().StringVarP(&vocabLang, "lang", "l", "en", "Language of the words")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVocab(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	out, err := runCommand(t, "", "vocab", "add", "House", "tree", "house")
+	if err != nil {
+		t.Fatalf("vocab add failed: %v\n%s", err, out)
+	}
+	if out != "Added 2 of 3 words\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	rootCmd.SetIn(strings.NewReader("# deck\nsauna,kylpy\nlöyly\n"))
+	defer rootCmd.SetIn(nil)
+	if out, err := runCommand(t, "", "vocab", "import", "-", "--lang", "fi"); err != nil || out != "Imported 2 new of 2 words\n" {
+		t.Errorf("vocab import = %q, %v", out, err)
+	}
+
+	if _, err := runCommand(t, "", "vocab", "remove", "tree"); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := runCommand(t, "", "vocab", "export"); out != "house\n" {
+		t.Errorf("export en = %q", out)
+	}
+	if out, _ := runCommand(t, "", "vocab", "export", "-l", "fi"); out != "löyly\nsauna\n" {
+		t.Errorf("export fi = %q", out)
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mjlefevre/sanoja/pkg/wiki"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wikiLang          string
+	wikiRandom        bool
+	wikiFormat        string
+	wikiAbsoluteLinks bool
+	wikiJSON          bool
+	textOnly          bool
+)
+
+// wikiFormats are the output formats accepted by --format
+var wikiFormats = []string{"html", "md", "text", "json"}
+
+var wikiCmd = &cobra.Command{
+	Use:   "wiki [TITLE|URL]",
+	Short: "Get a Wikipedia article",
+	Long: `Get a Wikipedia article by title or URL.
+
+Redirects are followed. If the title leads to a disambiguation page, the
+articles it lists are shown instead.
+
+JSON output is the structured article: the lead summary, the section tree,
+infobox fields, categories, internal and external links, references and images.
+
+Examples:
+  sanoja wiki Sauna                                # Get the English article on saunas
+  sanoja wiki Sauna --lang fi                      # Get the Finnish article
+  sanoja wiki https://fi.wikipedia.org/wiki/Sisu   # Get an article by URL
+  sanoja wiki "Helsinki Central Station" -t        # Get article text only
+  sanoja wiki Sauna --format md --absolute-links   # Get the article as Markdown
+  sanoja wiki Sauna --json                         # Get sections, infobox, links etc. as JSON
+  sanoja wiki --random --lang sv                   # Get a random Swedish article`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if wikiRandom {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		title := ""
+		if len(args) > 0 {
+			title = args[0]
+		}
+		return runWiki(cmd, title)
+	},
+}
+
+// runWiki fetches and prints an article, or a random article if title is empty
+func runWiki(cmd *cobra.Command, title string) error {
+	format, err := wikiOutputFormat()
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	client := wiki.NewClient(wiki.WithHTTPClient(httpClient))
+
+	var page *wiki.Page
+	if title == "" {
+		page, err = client.Random(wikiLang)
+	} else {
+		page, err = client.Fetch(title, wikiLang)
+	}
+
+	var disambiguation wiki.ErrDisambiguation
+	if errors.As(err, &disambiguation) {
+		writeDisambiguation(cmd.OutOrStdout(), disambiguation)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writePage(cmd.OutOrStdout(), page, format)
+}
+
+// wikiOutputFormat returns the --format to use, which -t and --json override
+func wikiOutputFormat() (string, error) {
+	if textOnly {
+		return "text", nil
+	}
+	if wikiJSON {
+		return "json", nil
+	}
+	switch wikiFormat {
+	case "markdown":
+		return "md", nil
+	case "html", "md", "text", "json":
+		return wikiFormat, nil
+	}
+	return "", fmt.Errorf("invalid format %q (expected one of %s)", wikiFormat, strings.Join(wikiFormats, ", "))
+}
+
+func writePage(out io.Writer, page *wiki.Page, format string) error {
+	var output string
+	switch format {
+	case "text":
+		output = page.Text()
+	case "md":
+		output = page.Markdown(wiki.MarkdownOptions{AbsoluteLinks: wikiAbsoluteLinks})
+		// Markdown output is a document of its own, titled with a heading
+		fmt.Fprintf(out, "# %s\n\n%s", page.Title, output)
+		return nil
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(page.Article())
+	default:
+		var err error
+		if output, err = page.HTML(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Title: %s\n", page.Title)
+	if page.RedirectedFrom != "" {
+		fmt.Fprintf(out, "Redirected from: %s\n", page.RedirectedFrom)
+	}
+	fmt.Fprintf(out, "\n%s\n", output)
+	return nil
+}
+
+func writeDisambiguation(out io.Writer, err wiki.ErrDisambiguation) {
+	fmt.Fprintf(out, "%s may refer to:\n", err.Title)
+	for _, option := range err.Options {
+		fmt.Fprintf(out, "  - %s\n", option.Description)
+	}
+}
+
+// addWikiFormatFlags adds the output flags shared by wiki and randwiki
+func addWikiFormatFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&wikiFormat, "format", "f", "html", "Output format: "+strings.Join(wikiFormats, ", "))
+	cmd.Flags().BoolVar(&wikiAbsoluteLinks, "absolute-links", false, "Make links in Markdown output absolute URLs")
+	cmd.Flags().BoolVarP(&textOnly, "text", "t", false, "Output text only (no HTML), same as --format text")
+	cmd.Flags().BoolVar(&wikiJSON, "json", false, "Output the structured article as JSON, same as --format json")
+}
+
+func init() {
+	rootCmd.AddCommand(wikiCmd)
+	wikiCmd.Flags().StringVarP(&wikiLang, "lang", "l", wiki.DefaultLanguage, "Wikipedia language edition, e.g. fi or sv")
+	addWikiFormatFlags(wikiCmd)
+	wikiCmd.Flags().BoolVar(&wikiRandom, "random", false, "Get a random article")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mjlefevre/sanoja/pkg/wiki"
+)
+
+func TestWiki(t *testing.T) {
+	out, err := runCommand(t, "wiki", "wiki", "Saunas", "-t")
+	if err != nil {
+		t.Fatalf("wiki failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "Title: Sauna\nRedirected from: Saunas\n\nA sauna") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestWikiLanguage(t *testing.T) {
+	out, err := runCommand(t, "wiki", "wiki", "Sauna", "--lang", "fi", "-t")
+	if err != nil {
+		t.Fatalf("wiki failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Sauna on huone tai rakennus, jossa kylvetään löylyssä. Saunominen") {
+		t.Errorf("expected the Finnish article, got:\n%s", out)
+	}
+
+	// The language of a URL wins over --lang
+	if out2, err := runCommand(t, "wiki", "wiki", "https://fi.wikipedia.org/wiki/Sauna", "-t"); err != nil || out2 != out {
+		t.Errorf("expected the same article by URL, got %v:\n%s", err, out2)
+	}
+}
+
+func TestWikiDisambiguation(t *testing.T) {
+	out, err := runCommand(t, "wiki", "wiki", "Sauna (disambiguation)")
+	if !errors.As(err, &wiki.ErrDisambiguation{}) {
+		t.Fatalf("expected a disambiguation error, got %v", err)
+	}
+	if !strings.HasPrefix(out, "Sauna (disambiguation) may refer to:\n  - Sauna (film), a 2008 Finnish horror film\n") {
+		t.Errorf("expected the options to be listed, got:\n%s", out)
+	}
+}
+
+func TestWikiNotFound(t *testing.T) {
+	_, err := runCommand(t, "wiki", "wiki", "No such article")
+	if err == nil || !strings.Contains(err.Error(), `does not have an article titled "No such article"`) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestWikiMarkdown(t *testing.T) {
+	out, err := runCommand(t, "wiki", "wiki", "Saunas", "--format", "md", "--absolute-links")
+	if err != nil {
+		t.Fatalf("wiki failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "# Sauna\n\n*This article is about the room.") {
+		t.Errorf("unexpected Markdown start:\n%s", out)
+	}
+	if !strings.Contains(out, "## Health effects") || !strings.Contains(out, "[Steam bath](https://en.wikipedia.org/wiki/Steam_bath)") {
+		t.Errorf("expected headings and absolute links:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "wiki", "wiki", "Saunas", "--format", "pdf"); err == nil || !strings.Contains(err.Error(), `invalid format "pdf"`) {
+		t.Errorf("expected invalid format error, got %v", err)
+	}
+}
+
+func TestWikiJSON(t *testing.T) {
+	out, err := runCommand(t, "wiki", "wiki", "Saunas", "--json")
+	if err != nil {
+		t.Fatalf("wiki failed: %v\n%s", err, out)
+	}
+
+	var article wiki.Article
+	if err := json.Unmarshal([]byte(out), &article); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if article.Title != "Sauna" || article.RedirectedFrom != "Saunas" || len(article.Sections) != 4 {
+		t.Errorf("unexpected article: %+v", article)
+	}
+	if len(article.References) != 2 || len(article.Categories) != 2 {
+		t.Errorf("references/categories = %v/%v", article.References, article.Categories)
+	}
+}
@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mjlefevre/sanoja/pkg/langid"
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/wiki"
+	"github.com/mjlefevre/sanoja/pkg/words"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wordsYouTube    string
+	wordsWiki       string
+	wordsRandomWiki bool
+	wordsLanguages  []string
+	wordsTop        int
+	wordsNGram      int
+	wordsJSON       bool
+	wordsStats      bool
+	wordsUnknown    bool
+	wordsAnki       string
+	wordsKeywords   bool
+	wordsDetect     bool
+)
+
+var wordsCmd = &cobra.Command{
+	Use:   "words [VIDEO|FILE|-]",
+	Short: "Analyse the words of a transcript, Wikipedia article, file or standard input",
+	Long: `Count the words or n-grams of a text. The text is read from one source:
+
+  --youtube VIDEO     the transcript of a YouTube video ID or URL
+  --wiki TITLE        a Wikipedia article by title or URL
+  --random-wiki       a random Wikipedia article
+  FILE                a local text file
+  VIDEO               the transcript of a YouTube video ID or URL, like --youtube
+  - or nothing        standard input
+
+An argument that names an existing file is read as a file, anything else as
+a video.
+
+--lang selects the transcript languages in order of preference, and the
+Wikipedia edition (the first language given).
+
+Examples:
+  sanoja words k82RwXqZHY8 --lang fi
+  sanoja words --wiki Sauna --lang fi --top 50
+  sanoja words --random-wiki --ngram 2
+  sanoja words notes.txt --json
+  sanoja wiki Sauna -t | sanoja words
+
+--stats shows readability and lexical complexity instead of word counts:
+type-token ratio, lexical density, average word and sentence length, the
+Flesch, Flesch-Kincaid, Gunning fog, Coleman-Liau, ARI and SMOG scores, the
+speaking rate of transcripts in words per minute and a CEFR-style level
+estimated from a bundled word list (English only for now). The readability
+formulas were designed for English and are only indicative for other
+languages.
+
+  sanoja words --stats k82RwXqZHY8
+
+--unknown lists the words you don't know yet, leaving out the known words
+managed with "sanoja vocab". Words are ranked by frequency and shown with
+the time they are first said and an example sentence. English words are
+stemmed with the Porter algorithm, so knowing "run" also covers "runs" and
+"running"; words in other languages are only matched as written, so each
+inflected form is listed separately. --anki writes them as a CSV deck that
+Anki can import (File > Import), with the word on the front and the example
+and a link to the video on the back. --top limits both.
+
+  sanoja words k82RwXqZHY8 --unknown
+  sanoja words k82RwXqZHY8 --unknown --top 0 --anki deck.csv
+
+--keywords extracts the key phrases of the text with RAKE: runs of words
+between stop words and punctuation, scored by how much their words keep
+company with others. Each phrase is shown with how often it occurs and, for
+transcripts, when. Stop word lists are bundled for en, fi, sv, de, fr and es;
+in other languages single words are ranked by frequency.
+
+  sanoja words k82RwXqZHY8 --keywords
+  sanoja words --keywords --wiki Sauna --lang fi --json
+
+The language of the text is identified offline from its letter combinations,
+and the analyses pick their stop words, stemmer and word lists for the
+detected language when the detection is reliable. Transcript tracks are
+sometimes labeled with the wrong language; a warning is shown when the text
+does not look like its declared language. --detect shows the confidence for
+each supported language (de, en, es, fi, fr, it, nl, pt and sv) instead.
+Text in other languages is reported as unknown, and a declared language
+outside this list is trusted. A --lang given on the command line wins over
+the detection when the transcript track or article is in that language.
+
+  sanoja words --detect --youtube k82RwXqZHY8
+  sanoja wiki Sauna -t | sanoja words --detect --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if wordsNGram < 1 {
+			return fmt.Errorf("--ngram must be at least 1")
+		}
+		unknown := wordsUnknown || wordsAnki != ""
+		modes := 0
+		for _, set := range []bool{wordsStats, unknown, wordsKeywords, wordsDetect} {
+			if set {
+				modes++
+			}
+		}
+		if modes > 1 {
+			return fmt.Errorf("use only one of --stats, --unknown, --keywords and --detect")
+		}
+
+		source, err := wordsSource(cmd, args)
+		if err != nil {
+			return err
+		}
+		doc, err := source.Load()
+		if err != nil {
+			return err
+		}
+
+		if wordsDetect {
+			return writeDetection(cmd.OutOrStdout(), doc)
+		}
+		warnLanguageMismatch(cmd.ErrOrStderr(), doc)
+
+		lang := wiki.DefaultLanguage
+		if len(wordsLanguages) > 0 {
+			lang = wordsLanguages[0]
+		}
+		doc.AnalysisLang = explicitLanguage(cmd, doc, wordsLanguages)
+		if wordsStats {
+			return writeStats(cmd.OutOrStdout(), doc, words.ComputeStats(doc, lang))
+		}
+		if unknown {
+			return runUnknownWords(cmd, doc, lang)
+		}
+		if wordsKeywords {
+			return writeKeywords(cmd.OutOrStdout(), doc, words.ExtractKeywords(doc, lang, wordsTop))
+		}
+		return writeAnalysis(cmd.OutOrStdout(), doc, words.Analyze(doc, wordsNGram, wordsTop))
+	},
+}
+
+// wordsSource picks the document source from the flags and arguments
+func wordsSource(cmd *cobra.Command, args []string) (words.Source, error) {
+	given := 0
+	for _, set := range []bool{wordsYouTube != "", wordsWiki != "", wordsRandomWiki, len(args) > 0} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return nil, fmt.Errorf("give only one of --youtube, --wiki, --random-wiki or VIDEO|FILE")
+	}
+
+	lang := wiki.DefaultLanguage
+	if len(wordsLanguages) > 0 {
+		lang = wordsLanguages[0]
+	}
+
+	video := wordsYouTube
+	if len(args) > 0 && args[0] != "-" {
+		videoID, err := videoOrFile(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if videoID == "" {
+			return words.FileSource{Path: args[0]}, nil
+		}
+		video = videoID
+	}
+
+	switch {
+	case video != "":
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		client := transcript.NewClient(transcript.WithHTTPClient(httpClient), transcript.WithCacheDir(cacheDir))
+		return words.TranscriptSource{Client: client, Video: video, Languages: wordsLanguages}, nil
+	case wordsWiki != "" || wordsRandomWiki:
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		return words.WikiSource{Client: wiki.NewClient(wiki.WithHTTPClient(httpClient)), Title: wordsWiki, Lang: lang}, nil
+	default:
+		return words.ReaderSource{Name: "stdin", Reader: cmd.InOrStdin()}, nil
+	}
+}
+
+// videoOrFile resolves a document argument: it returns an empty video ID for
+// an existing file, and the video ID of anything else that is a YouTube URL
+// or video ID
+func videoOrFile(arg string) (string, error) {
+	if _, err := os.Stat(arg); err == nil {
+		return "", nil
+	}
+	videoID := transcript.ExtractVideoID(arg)
+	if videoID == "" {
+		return "", fmt.Errorf("%s is neither a file nor a YouTube URL or Video ID", arg)
+	}
+	return videoID, nil
+}
+
+func writeAnalysis(out io.Writer, doc *words.Document, analysis *words.Analysis) error {
+	if wordsJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Title    string `json:"title"`
+			Source   string `json:"source"`
+			Location string `json:"location,omitempty"`
+			Lang     string `json:"lang,omitempty"`
+			*words.Analysis
+		}{doc.Title, doc.Source, doc.Location, doc.Lang, analysis})
+	}
+
+	fmt.Fprintf(out, "%s (%s)\n", doc.Title, doc.Source)
+	fmt.Fprintf(out, "Words: %d, unique: %d\n\n", analysis.Tokens, analysis.Unique)
+
+	fmt.Fprintln(out, "COUNT  TERM")
+	for _, term := range analysis.Terms {
+		fmt.Fprintf(out, "%5d  %s\n", term.Count, term.Term)
+	}
+	return nil
+}
+
+func writeStats(out io.Writer, doc *words.Document, stats *words.Stats) error {
+	if wordsJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Title    string `json:"title"`
+			Source   string `json:"source"`
+			Location string `json:"location,omitempty"`
+			Lang     string `json:"lang,omitempty"`
+			*words.Stats
+		}{doc.Title, doc.Source, doc.Location, doc.Lang, stats})
+	}
+
+	line := func(label, format string, args ...any) {
+		fmt.Fprintf(out, "%-22s %s\n", label+":", fmt.Sprintf(format, args...))
+	}
+
+	fmt.Fprintf(out, "%s (%s)\n", doc.Title, doc.Source)
+	line("Words", "%d", stats.Words)
+	line("Unique words", "%d", stats.Unique)
+	if stats.SentencesEstimated {
+		line("Sentences", "%d (estimated, the text has no punctuation)", stats.Sentences)
+	} else {
+		line("Sentences", "%d", stats.Sentences)
+	}
+	line("Type-token ratio", "%.3f", stats.TypeTokenRatio)
+	if stats.LexicalDensity > 0 {
+		line("Lexical density", "%.3f", stats.LexicalDensity)
+	}
+	line("Avg word length", "%.2f letters", stats.AvgWordLength)
+	line("Avg sentence length", "%.2f words", stats.AvgSentenceLength)
+	line("Avg syllables", "%.2f per word", stats.AvgSyllables)
+
+	r := stats.Readability
+	fmt.Fprintln(out)
+	line("Flesch reading ease", "%.1f", r.FleschReadingEase)
+	line("Flesch-Kincaid grade", "%.1f", r.FleschKincaidGrade)
+	line("Gunning fog", "%.1f", r.GunningFog)
+	line("Coleman-Liau", "%.1f", r.ColemanLiau)
+	line("ARI", "%.1f", r.AutomatedReadability)
+	line("SMOG", "%.1f", r.SMOG)
+
+	if stats.WordsPerMinute > 0 {
+		fmt.Fprintln(out)
+		line("Speaking rate", "%.1f words per minute", stats.WordsPerMinute)
+		line("Speaking time", "%s of %s", formatSeconds(stats.SpeakingTime), formatSeconds(stats.Duration))
+	}
+
+	if d := stats.Difficulty; d != nil {
+		fmt.Fprintln(out)
+		line("Level", "%s", d.Level)
+		var coverage []string
+		for _, c := range d.Coverage {
+			coverage = append(coverage, fmt.Sprintf("%s %.1f%%", c.Level, c.Percent))
+		}
+		line("Words known", "%s", strings.Join(coverage, ", "))
+		if d.Names > 0 {
+			line("Names left out", "%d", d.Names)
+		}
+	}
+	return nil
+}
+
+// runUnknownWords lists the words of a document missing from the known
+// words and writes them to an Anki deck if asked to
+func runUnknownWords(cmd *cobra.Command, doc *words.Document, lang string) error {
+	_, known, err := loadKnownWords()
+	if err != nil {
+		return err
+	}
+	unknown := words.UnknownWords(doc, known, lang)
+	if wordsTop > 0 && len(unknown) > wordsTop {
+		unknown = unknown[:wordsTop]
+	}
+
+	if wordsAnki != "" {
+		if wordsAnki == "-" {
+			return words.WriteAnki(cmd.OutOrStdout(), doc, unknown)
+		}
+		f, err := os.Create(wordsAnki)
+		if err != nil {
+			return fmt.Errorf("error creating Anki deck: %v", err)
+		}
+		if err := words.WriteAnki(f, doc, unknown); err != nil {
+			f.Close()
+			return fmt.Errorf("error writing Anki deck: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("error writing Anki deck: %v", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d cards to %s\n", len(unknown), wordsAnki)
+		if !wordsUnknown {
+			return nil
+		}
+	}
+	return writeUnknownWords(cmd.OutOrStdout(), doc, unknown)
+}
+
+func writeUnknownWords(out io.Writer, doc *words.Document, unknown []words.UnknownWord) error {
+	if wordsJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Title    string              `json:"title"`
+			Source   string              `json:"source"`
+			Location string              `json:"location,omitempty"`
+			Lang     string              `json:"lang,omitempty"`
+			Unknown  []words.UnknownWord `json:"unknown"`
+		}{doc.Title, doc.Source, doc.Location, doc.Lang, unknown})
+	}
+
+	fmt.Fprintf(out, "%s (%s)\n", doc.Title, doc.Source)
+	if len(unknown) == 0 {
+		fmt.Fprintln(out, "No unknown words")
+		return nil
+	}
+	fmt.Fprintf(out, "Unknown words: %d\n\n", len(unknown))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if doc.Timed {
+		fmt.Fprintln(w, "COUNT\tTIME\tWORD\tEXAMPLE")
+	} else {
+		fmt.Fprintln(w, "COUNT\tWORD\tEXAMPLE")
+	}
+	for _, word := range unknown {
+		if word.Start != nil {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", word.Count, formatTimestamp(*word.Start), word.Word, word.Example)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", word.Count, word.Word, word.Example)
+		}
+	}
+	return w.Flush()
+}
+
+// maxKeywordTimes is the number of occurrence times listed per key phrase
+const maxKeywordTimes = 5
+
+func writeKeywords(out io.Writer, doc *words.Document, keywords []words.Keyword) error {
+	if wordsJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Title    string          `json:"title"`
+			Source   string          `json:"source"`
+			Location string          `json:"location,omitempty"`
+			Lang     string          `json:"lang,omitempty"`
+			Keywords []words.Keyword `json:"keywords"`
+		}{doc.Title, doc.Source, doc.Location, doc.Lang, keywords})
+	}
+
+	fmt.Fprintf(out, "%s (%s)\n", doc.Title, doc.Source)
+	if len(keywords) == 0 {
+		fmt.Fprintln(out, "No key phrases")
+		return nil
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if doc.Timed {
+		fmt.Fprintln(w, "SCORE\tCOUNT\tPHRASE\tTIMES")
+	} else {
+		fmt.Fprintln(w, "SCORE\tCOUNT\tPHRASE")
+	}
+	for _, k := range keywords {
+		if !doc.Timed {
+			fmt.Fprintf(w, "%.2f\t%d\t%s\n", k.Score, k.Count, k.Phrase)
+			continue
+		}
+		var times []string
+		for _, start := range k.Starts[:min(len(k.Starts), maxKeywordTimes)] {
+			times = append(times, formatTimestamp(start))
+		}
+		if len(k.Starts) > maxKeywordTimes {
+			times = append(times, "…")
+		}
+		fmt.Fprintf(w, "%.2f\t%d\t%s\t%s\n", k.Score, k.Count, k.Phrase, strings.Join(times, " "))
+	}
+	return w.Flush()
+}
+
+func writeDetection(out io.Writer, doc *words.Document) error {
+	detected := doc.DetectLanguage()
+	mismatch := detected.Mismatch(doc.Lang)
+	if wordsJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Title    string        `json:"title"`
+			Source   string        `json:"source"`
+			Location string        `json:"location,omitempty"`
+			Lang     string        `json:"lang,omitempty"`
+			Detected langid.Result `json:"detected"`
+			Mismatch bool          `json:"mismatch"`
+		}{doc.Title, doc.Source, doc.Location, doc.Lang, detected, mismatch})
+	}
+
+	fmt.Fprintf(out, "%s (%s)\n", doc.Title, doc.Source)
+	if len(detected.Scores) == 0 {
+		fmt.Fprintln(out, "No text to identify")
+		return nil
+	}
+	fmt.Fprintf(out, "Declared: %s\n", orNone(doc.Lang))
+	if detected.Language == "" {
+		fmt.Fprintf(out, "Detected: unknown (not one of %s)\n", strings.Join(langid.Languages(), ", "))
+	} else {
+		reliability := "reliable"
+		if !detected.Reliable {
+			reliability = "unreliable"
+		}
+		fmt.Fprintf(out, "Detected: %s (confidence %.2f, %s)\n", detected.Language, detected.Confidence, reliability)
+	}
+	if mismatch {
+		fmt.Fprintf(out, "The text does not look like its declared language %s\n", doc.Lang)
+	}
+	fmt.Fprintln(out)
+
+	scores := detected.Scores
+	if wordsTop > 0 && len(scores) > wordsTop {
+		scores = scores[:wordsTop]
+	}
+	fmt.Fprintln(out, "LANG  CONFIDENCE")
+	for _, score := range scores {
+		fmt.Fprintf(out, "%-4s  %10.2f\n", score.Language, score.Confidence)
+	}
+	return nil
+}
+
+// warnLanguageMismatch warns when a document does not look like its declared language
+func warnLanguageMismatch(out io.Writer, doc *words.Document) {
+	if detected := doc.DetectLanguage(); detected.Mismatch(doc.Lang) {
+		fmt.Fprintf(out, "Warning: %s is declared as %s but looks like %s (confidence %.2f)\n",
+			doc.Title, doc.Lang, detected.Language, detected.Confidence)
+	}
+}
+
+// formatTimestamp formats a number of seconds as a video position like 1:05
+func formatTimestamp(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// formatSeconds formats a number of seconds as a duration like 1m23s
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
+
+func init() {
+	rootCmd.AddCommand(wordsCmd)
+	wordsCmd.Flags().StringVar(&wordsYouTube, "youtube", "", "Analyse the transcript of this YouTube video ID or URL")
+	wordsCmd.Flags().StringVar(&wordsWiki, "wiki", "", "Analyse this Wikipedia article title or URL")
+	wordsCmd.Flags().BoolVar(&wordsRandomWiki, "random-wiki", false, "Analyse a random Wikipedia article")
+	wordsCmd.Flags().StringSliceVarP(&wordsLanguages, "lang", "l", []string{"en"}, "Transcript languages in order of preference; the first is also the Wikipedia edition")
+	wordsCmd.Flags().IntVarP(&wordsTop, "top", "n", 20, "Number of most frequent terms to show, 0 for all")
+	wordsCmd.Flags().IntVar(&wordsNGram, "ngram", 1, "Count sequences of this many words instead of single words")
+	wordsCmd.Flags().BoolVar(&wordsJSON, "json", false, "Output the analysis as JSON")
+	wordsCmd.Flags().BoolVar(&wordsStats, "stats", false, "Show readability, lexical complexity and speaking rate instead of word counts")
+	wordsCmd.Flags().BoolVar(&wordsUnknown, "unknown", false, "List the words missing from your known words (see \"sanoja vocab\")")
+	wordsCmd.Flags().BoolVar(&wordsKeywords, "keywords", false, "Show the key phrases of the text with their counts and times")
+	wordsCmd.Flags().BoolVar(&wordsDetect, "detect", false, "Identify the language of the text and compare it with the declared one")
+	wordsCmd.Flags().StringVar(&wordsAnki, "anki", "", "Write the unknown words to this file as an Anki CSV deck, - for standard output")
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/words"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareLanguages   []string
+	compareTop         int
+	compareJSON        bool
+	compareConcurrency int
+)
+
+var wordsCompareCmd = &cobra.Command{
+	Use:   "compare VIDEO|FILE VIDEO|FILE [...]",
+	Short: "Compare the vocabulary of several videos or files",
+	Long: `Compare the vocabulary of YouTube transcripts or local text files.
+
+Each argument is a YouTube video ID or URL, or the path of a text file.
+Transcripts are fetched concurrently.
+
+For every document the most frequent and the most distinctive words are
+shown. A word is distinctive when it is frequent in one document and rare in
+the others, as measured by TF-IDF; words used in every document are shared
+vocabulary. Every two documents are compared with the Jaccard similarity of
+their words and the cosine similarity of their word counts.
+
+Examples:
+  sanoja words compare k82RwXqZHY8 dQw4w9WgXcQ
+  sanoja words compare k82RwXqZHY8 dQw4w9WgXcQ jNQXAC9IVRw --top 20
+  sanoja words compare k82RwXqZHY8 notes.txt --json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		docs, err := compareDocuments(args)
+		if err != nil {
+			return err
+		}
+		return writeComparison(cmd.OutOrStdout(), words.Compare(docs, compareTop))
+	},
+}
+
+// compareDocuments loads the documents to compare, fetching the transcripts
+// of all videos in one batch
+func compareDocuments(args []string) ([]*words.Document, error) {
+	docs := make([]*words.Document, len(args))
+	var videoIDs []string
+	var positions []int
+	for i, arg := range args {
+		videoID, err := videoOrFile(arg)
+		if err != nil {
+			return nil, err
+		}
+		if videoID == "" {
+			doc, err := words.FileSource{Path: arg}.Load()
+			if err != nil {
+				return nil, err
+			}
+			docs[i] = doc
+			continue
+		}
+		videoIDs = append(videoIDs, videoID)
+		positions = append(positions, i)
+	}
+	if len(videoIDs) == 0 {
+		return docs, nil
+	}
+
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	client := transcript.NewClient(transcript.WithHTTPClient(httpClient), transcript.WithCacheDir(cacheDir))
+	for i, result := range client.FetchTranscripts(videoIDs, compareLanguages, compareConcurrency) {
+		if result.Err != nil {
+			return nil, fmt.Errorf("error fetching transcript for %s: %v", result.VideoID, result.Err)
+		}
+		docs[positions[i]] = words.NewTranscriptDocument(result.VideoID, result.Entries)
+	}
+	return docs, nil
+}
+
+func writeComparison(out io.Writer, c *words.Comparison) error {
+	if compareJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(c)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tDOCUMENT\tSOURCE\tWORDS\tUNIQUE")
+	for i, d := range c.Documents {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\n", i+1, d.Title, d.Source, d.Tokens, d.Unique)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "A\tB\tJACCARD\tCOSINE\tSHARED")
+	for _, p := range c.Pairs {
+		fmt.Fprintf(w, "%d\t%d\t%.3f\t%.3f\t%d\n", p.A+1, p.B+1, p.Jaccard, p.Cosine, p.Shared)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nShared by all (%d words):\n  %s\n", c.SharedTotal, joinCounts(c.Shared))
+	for i, d := range c.Documents {
+		var distinctive []string
+		for _, term := range d.Distinctive {
+			distinctive = append(distinctive, fmt.Sprintf("%s %d", term.Term, term.Count))
+		}
+		fmt.Fprintf(out, "\n%d %s\n", i+1, d.Title)
+		fmt.Fprintf(out, "  Most frequent: %s\n", joinCounts(d.Top))
+		fmt.Fprintf(out, "  Distinctive:   %s\n", orNone(strings.Join(distinctive, ", ")))
+	}
+	return nil
+}
+
+// joinCounts lists terms with their counts, e.g. "sauna 3, on 2"
+func joinCounts(counts []words.Count) string {
+	var parts []string
+	for _, c := range counts {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Term, c.Count))
+	}
+	return orNone(strings.Join(parts, ", "))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func init() {
+	wordsCmd.AddCommand(wordsCompareCmd)
+	wordsCompareCmd.Flags().StringSliceVarP(&compareLanguages, "lang", "l", []string{"en"}, "Transcript languages in order of preference")
+	wordsCompareCmd.Flags().IntVarP(&compareTop, "top", "n", 10, "Number of frequent, distinctive and shared words to show, 0 for all")
+	wordsCompareCmd.Flags().BoolVar(&compareJSON, "json", false, "Output the comparison as JSON")
+	wordsCompareCmd.Flags().IntVar(&compareConcurrency, "concurrency", 4, "Maximum number of transcripts fetched at once")
+}
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjlefevre/sanoja/pkg/langid"
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+func TestWordsYouTube(t *testing.T) {
+	out, err := runCommand(t, "ytt", "words", "--youtube", "k82RwXqZHY8", "--top", "3")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	want := "video k82RwXqZHY8 (youtube)\nWords: 21, unique: 20\n\nCOUNT  TERM\n    2  word\n    1  and\n    1  basic\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestWordsWikiJSON(t *testing.T) {
+	out, err := runCommand(t, "wiki", "words", "--wiki", "Sauna", "--lang", "fi", "--ngram", "2", "--json")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+
+	var result struct {
+		Title  string `json:"title"`
+		Source string `json:"source"`
+		Lang   string `json:"lang"`
+		N      int    `json:"n"`
+		Terms  []struct {
+			Term  string `json:"term"`
+			Count int    `json:"count"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Title != "Sauna" || result.Source != "wikipedia" || result.Lang != "fi" || result.N != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Terms) == 0 || !strings.Contains(result.Terms[0].Term, " ") {
+		t.Errorf("expected bigrams, got %+v", result.Terms)
+	}
+}
+
+func TestWordsStdin(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader("Sauna on kuuma. Sauna on hyvä."))
+	defer rootCmd.SetIn(nil)
+
+	out, err := runCommand(t, "", "words", "-n", "1")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "stdin (stdin)\nWords: 6, unique: 4\n") || !strings.HasSuffix(out, "2  on\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "", "words", "--wiki", "Sauna", "notes.txt"); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Errorf("expected conflicting sources to be rejected, got %v", err)
+	}
+}
+
+func TestWordsArgument(t *testing.T) {
+	out, err := runCommand(t, "ytt", "words", "https://www.youtube.com/watch?v=k82RwXqZHY8", "--top", "1")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "video k82RwXqZHY8 (youtube)\nWords: 21") {
+		t.Errorf("expected the transcript of the video, got:\n%s", out)
+	}
+
+	// An existing file wins over a video ID of the same name
+	dir := t.TempDir()
+	path := filepath.Join(dir, "k82RwXqZHY8")
+	if err := os.WriteFile(path, []byte("Sauna on kuuma."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCommand(t, "", "words", path)
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "k82RwXqZHY8 (file)\nWords: 3") {
+		t.Errorf("expected the file, got:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "", "words", "missing.txt"); err == nil || !strings.Contains(err.Error(), "neither a file nor a YouTube URL or Video ID") {
+		t.Errorf("expected an argument that is neither a file nor a video to be rejected, got %v", err)
+	}
+}
+
+func TestWordsStats(t *testing.T) {
+	out, err := runCommand(t, "ytt", "words", "--stats", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"Words:                 21\n",
+		"Sentences:             4\n",
+		"Type-token ratio:      0.952\n",
+		"Flesch reading ease:   84.7\n",
+		"Speaking rate:         126.0 words per minute\n",
+		"Speaking time:         10s of 10s\n",
+		// hello and welcome are left out of the word list, see its header
+		"Level:                 C2\n",
+		"Words known:           A1 75.0%, A2 75.0%, B1 80.0%, B2 90.0%, C1 90.0%, C2 100.0%\n",
+		"Names left out:        1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "COUNT  TERM") {
+		t.Errorf("--stats should replace the word counts:\n%s", out)
+	}
+
+	out, err = runCommand(t, "ytt", "words", "--stats", "--json", "--youtube", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	var result struct {
+		Words          int     `json:"words"`
+		WordsPerMinute float64 `json:"wordsPerMinute"`
+		Difficulty     struct {
+			Level string `json:"level"`
+		} `json:"difficulty"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Words != 21 || result.WordsPerMinute != 126 || result.Difficulty.Level != "C2" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestWordsUnknown(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if _, err := runCommand(t, "", "vocab", "add", "hello", "and", "to", "this", "the", "word", "means", "we", "some", "let's"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCommand(t, "ytt", "words", "k82RwXqZHY8", "--unknown", "--top", "3")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	// "words" is known through "word", and Finnish is a name
+	want := "video k82RwXqZHY8 (youtube)\nUnknown words: 3\n\n" +
+		"COUNT  TIME  WORD     EXAMPLE\n" +
+		"1      0:00  welcome  Hello and welcome to this video.\n" +
+		"1      0:00  video    Hello and welcome to this video.\n" +
+		"1      0:02  today    Today we learn some basic Finnish words.\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+
+	out, err = runCommand(t, "ytt", "words", "k82RwXqZHY8", "--top", "0", "--anki", "-")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "#separator:Comma\n") || strings.Count(out, "sanoja youtube\n") != 8 {
+		t.Errorf("unexpected deck:\n%s", out)
+	}
+	if !strings.Contains(out, `sana,"The word &#34;<b>sana</b>&#34; means word.`) {
+		t.Errorf("expected the word in bold in its example:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "ytt", "words", "--unknown", "--stats", "--youtube", "k82RwXqZHY8"); err == nil {
+		t.Error("expected --stats and --unknown to be rejected together")
+	}
+}
+
+func TestWordsCompare(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(path, []byte("Today we learn Finnish words. The word sauna means sauna.\n"), 0o644)
+
+	out, err := runCommand(t, "ytt", "words", "compare", "k82RwXqZHY8", path, "--top", "3")
+	if err != nil {
+		t.Fatalf("words compare failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"1  video k82RwXqZHY8  youtube  21     20\n",
+		"2  notes.txt          file     10     9\n",
+		"1  2  0.381    0.542   8\n",
+		"Shared by all (8 words):\n  word 3, finnish 2, learn 2\n",
+		"2 notes.txt\n  Most frequent: sauna 2, finnish 1, learn 1\n  Distinctive:   sauna 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, "ytt", "words", "compare", "https://youtu.be/k82RwXqZHY8", path, "--json")
+	if err != nil {
+		t.Fatalf("words compare failed: %v\n%s", err, out)
+	}
+	var result words.Comparison
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(result.Documents) != 2 || result.Documents[0].Title != "video k82RwXqZHY8" || len(result.Pairs) != 1 {
+		t.Errorf("unexpected comparison: %+v", result)
+	}
+
+	if _, err := runCommand(t, "", "words", "compare", "k82RwXqZHY8"); err == nil {
+		t.Error("expected a single document to be rejected")
+	}
+}
+
+func TestWordsKeywords(t *testing.T) {
+	out, err := runCommand(t, "ytt", "words", "k82RwXqZHY8", "--keywords", "--top", "2")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	want := "video k82RwXqZHY8 (youtube)\n\n" +
+		"SCORE  COUNT  PHRASE               TIMES\n" +
+		"9.00   1      basic finnish words  0:02\n" +
+		"1.00   2      word                 0:06 0:06\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+
+	out, err = runCommand(t, "wiki", "words", "--keywords", "--wiki", "Sauna", "--lang", "fi", "--json")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	var result struct {
+		Keywords []words.Keyword `json:"keywords"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(result.Keywords) == 0 || result.Keywords[0].Phrase != "tärkeä osa suomalaista kulttuuria" || result.Keywords[0].First != nil {
+		t.Errorf("unexpected keywords: %+v", result.Keywords)
+	}
+
+	if _, err := runCommand(t, "ytt", "words", "k82RwXqZHY8", "--keywords", "--stats"); err == nil {
+		t.Error("expected --keywords and --stats to be rejected together")
+	}
+}
+
+func TestWordsExplicitLang(t *testing.T) {
+	// The mislabeled track is Finnish, for which there is no word list
+	out, err := runCommand(t, "ytt_mislabeled", "words", "Nd4fT0sA9wE", "--stats")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "Level:") {
+		t.Errorf("expected the detected fi to be used, got:\n%s", out)
+	}
+
+	out, err = runCommand(t, "ytt_mislabeled", "words", "Nd4fT0sA9wE", "--stats", "--lang", "en")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Level:") {
+		t.Errorf("expected the explicit --lang en to win over the detection, got:\n%s", out)
+	}
+
+	// A --lang from the environment is only a transcript preference
+	t.Setenv("SANOJA_WORDS_LANG", "en")
+	out, err = runCommand(t, "ytt_mislabeled", "words", "Nd4fT0sA9wE", "--stats")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "Level:") {
+		t.Errorf("expected the detected fi to be used with --lang from the environment, got:\n%s", out)
+	}
+}
+
+func TestWordsExplicitLangFallback(t *testing.T) {
+	// There is no Swedish track, so the English one is analysed in English
+	want := "video k82RwXqZHY8 (youtube)\n\n" +
+		"SCORE  COUNT  PHRASE               TIMES\n" +
+		"9.00   1      basic finnish words  0:02\n" +
+		"1.00   2      word                 0:06 0:06\n"
+	for _, lang := range []string{"sv,en", "sv"} {
+		out, err := runCommand(t, "ytt", "words", "--lang", lang, "k82RwXqZHY8", "--keywords", "--top", "2")
+		if err != nil {
+			t.Fatalf("words failed: %v\n%s", err, out)
+		}
+		if out != want {
+			t.Errorf("--lang %s: output =\n%s\nwant\n%s", lang, out, want)
+		}
+	}
+}
+
+func TestWordsDetect(t *testing.T) {
+	out, err := runCommand(t, "ytt", "words", "--detect", "--youtube", "k82RwXqZHY8", "--top", "2")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "video k82RwXqZHY8 (youtube)\nDeclared: en\nDetected: en (confidence ") ||
+		!strings.Contains(out, "reliable)\n\nLANG  CONFIDENCE\nen  ") || strings.Contains(out, "does not look like") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 7 {
+		t.Errorf("expected --top to limit the languages, got:\n%s", out)
+	}
+
+	out, err = runCommand(t, "ytt_mislabeled", "words", "--detect", "--json", "--youtube", "Nd4fT0sA9wE")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	var result struct {
+		Lang     string        `json:"lang"`
+		Detected langid.Result `json:"detected"`
+		Mismatch bool          `json:"mismatch"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Lang != "en" || result.Detected.Language != "fi" || !result.Mismatch || len(result.Detected.Scores) != len(langid.Languages()) {
+		t.Errorf("unexpected detection: %+v", result)
+	}
+
+	rootCmd.SetIn(strings.NewReader("Ich habe heute keine Zeit, weil ich arbeiten muss."))
+	defer rootCmd.SetIn(nil)
+	out, err = runCommand(t, "", "words", "--detect")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Declared: none\nDetected: de ") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	rootCmd.SetIn(strings.NewReader("Tere kõigile ja tere tulemast minu kanalile täna räägime toidust."))
+	out, err = runCommand(t, "", "words", "--detect")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Detected: unknown (not one of de, en, es, fi, fr, it, nl, pt, sv)\n") {
+		t.Errorf("expected Estonian to be an unknown language, got:\n%s", out)
+	}
+
+	if _, err := runCommand(t, "ytt", "words", "--detect", "--stats", "--youtube", "k82RwXqZHY8"); err == nil {
+		t.Error("expected --detect and --stats to be rejected together")
+	}
+}
+
+func TestWordsMislabeledTranscript(t *testing.T) {
+	out, err := runCommand(t, "ytt_mislabeled", "words", "--keywords", "--youtube", "Nd4fT0sA9wE")
+	if err != nil {
+		t.Fatalf("words failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "Warning: video Nd4fT0sA9wE is declared as en but looks like fi") {
+		t.Errorf("expected a warning about the mislabeled track, got:\n%s", out)
+	}
+	// Finnish stop words split the captions into phrases; with English ones
+	// every caption would be a single phrase
+	if !strings.Contains(out, "heitetään löylyä") {
+		t.Errorf("expected Finnish stop words to be used, got:\n%s", out)
+	}
+}
@@ -6,9 +6,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
-	"github.com/mjlefevre/sanoja/internal/browser"
 	"github.com/spf13/cobra"
 )
 
@@ -33,7 +31,7 @@ Examples:
 		}
 
 		url := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-		fmt.Println("Fetching URL:", url)
+		fmt.Fprintln(cmd.OutOrStdout(), "Fetching URL:", url)
 
 		// Create request with headers
 		req, err := http.NewRequest("GET", url, nil)
@@ -41,12 +39,9 @@ Examples:
 			return fmt.Errorf("error creating request: %v", err)
 		}
 
-		// Set default headers
-		browser.SetDefaultHeaders(req)
-
-		// Create client with timeout
-		client := &http.Client{
-			Timeout: 10 * time.Second,
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
 		}
 
 		// Get the page
@@ -107,7 +102,7 @@ Examples:
 		description = strings.TrimSpace(description)
 		description = strings.ReplaceAll(description, "\n\n\n", "\n")
 
-		fmt.Printf("Description for video %s:\n%s\n", videoID, description)
+		fmt.Fprintf(cmd.OutOrStdout(), "Description for video %s:\n%s\n", videoID, description)
 		return nil
 	},
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestYtdb(t *testing.T) {
+	out, err := runCommand(t, "ytdb", "ytdb", "https://www.youtube.com/watch?v=k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytdb failed: %v\n%s", err, out)
+	}
+
+	want := `Fetching URL: https://www.youtube.com/watch?v=k82RwXqZHY8
+Description for video k82RwXqZHY8:
+Finnish basics for beginners.
+Greetings, numbers and everyday words.
+
+Subscribe for more!
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestExtractVideoID(t *testing.T) {
+	tests := map[string]string{
+		"k82RwXqZHY8": "k82RwXqZHY8",
+		"https://www.youtube.com/watch?v=k82RwXqZHY8":       "k82RwXqZHY8",
+		"https://www.youtube.com/watch?v=k82RwXqZHY8&t=42s": "k82RwXqZHY8",
+		"https://youtu.be/k82RwXqZHY8":                      "k82RwXqZHY8",
+	}
+	for input, want := range tests {
+		if got := extractVideoID(input); got != want {
+			t.Errorf("extractVideoID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
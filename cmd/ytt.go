@@ -1,12 +1,29 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/mjlefevre/sanoja/pkg/summary"
 	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/words"
 	"github.com/spf13/cobra"
 )
 
+var (
+	yttLanguages []string
+	yttFormat    string
+
+	yttTimeline bool
+	yttWindow   time.Duration
+	yttStep     time.Duration
+	yttMinGap   time.Duration
+
+	yttSummary int
+)
+
 var yttCmd = &cobra.Command{
 	Use:   "ytt [VIDEO]",
 	Short: "Get a YouTube video transcript from a YouTube video ID or URL",
@@ -18,7 +35,25 @@ VIDEO can be either:
 
 Examples:
   sanoja ytt k82RwXqZHY8
-  sanoja ytt https://www.youtube.com/watch?v=k82RwXqZHY8`,
+  sanoja ytt https://www.youtube.com/watch?v=k82RwXqZHY8
+  sanoja ytt --cookies cookies.txt k82RwXqZHY8   # Use an authenticated session
+  sanoja ytt --lang fi,en k82RwXqZHY8            # Prefer Finnish, then English
+  sanoja ytt --format json k82RwXqZHY8           # Output entries with timestamps
+  sanoja ytt --timeline k82RwXqZHY8              # Show the speaking rate over time
+  sanoja ytt --timeline --format csv --window 1m k82RwXqZHY8
+
+--timeline shows how fast the video is spoken: words per minute over sliding
+windows drawn as a sparkline, the gaps between captions (pauses, music) and
+the bursts of speech faster than 1.5 times the average. With --format csv it
+prints the windows as rows, with --format json the whole series for charting.
+
+--summary N picks the N most central sentences of the transcript with
+TextRank and shows them in order with their timestamps. It works offline.
+
+  sanoja ytt --summary 10 k82RwXqZHY8
+
+A warning is shown when the transcript does not look like the language its
+track is labeled with; auto-generated tracks are sometimes mislabeled.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		input := args[0]
@@ -26,14 +61,94 @@ Examples:
 		if videoID == "" {
 			return fmt.Errorf("invalid YouTube URL or Video ID: %s", input)
 		}
+		if yttTimeline && yttSummary > 0 {
+			return fmt.Errorf("--timeline and --summary cannot be used together")
+		}
+		if yttTimeline && yttStep.Seconds() < words.MinTimelineStep {
+			return fmt.Errorf("--step must be at least %v", time.Duration(words.MinTimelineStep*float64(time.Second)))
+		}
+
+		httpClient, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
 
-		client := transcript.NewClient()
-		transcriptText, err := client.GetTranscriptString(videoID)
+		client := transcript.NewClient(
+			transcript.WithHTTPClient(httpClient),
+			transcript.WithCacheDir(cacheDir),
+		)
+		entries, track, err := client.GetTranscriptTrack(videoID, yttLanguages)
 		if err != nil {
 			return fmt.Errorf("error fetching transcript: %v", err)
 		}
+		doc := words.NewTranscriptDocument(videoID, entries)
+		doc.Lang = track.LanguageCode
+		warnLanguageMismatch(cmd.ErrOrStderr(), doc)
 
-		fmt.Printf("Transcript for video %s:\n%s\n", videoID, transcriptText)
-		return nil
+		if yttSummary > 0 {
+			// Summarize in the language of the track YouTube returned, which
+			// is not the preferred one if the video has no such track
+			lang := track.LanguageCode
+			if lang == "" {
+				lang = "en"
+			}
+			doc.AnalysisLang = explicitLanguage(cmd, doc, yttLanguages)
+			return writeSummary(cmd.OutOrStdout(), videoID, yttFormat, summary.Summarize(doc, lang, yttSummary))
+		}
+		if yttTimeline {
+			tl := words.AnalyzeTimeline(doc, words.TimelineOptions{
+				Window: yttWindow.Seconds(),
+				Step:   yttStep.Seconds(),
+				MinGap: yttMinGap.Seconds(),
+			})
+			return writeTimeline(cmd.OutOrStdout(), videoID, yttFormat, tl)
+		}
+
+		switch yttFormat {
+		case "json":
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(map[string]interface{}{
+				"videoId": videoID,
+				"text":    transcript.ConcatenateTranscript(entries),
+				"entries": entries,
+			})
+		case "text":
+			fmt.Fprintf(cmd.OutOrStdout(), "Transcript for video %s:\n%s\n", videoID, transcript.ConcatenateTranscript(entries))
+			return nil
+		default:
+			return fmt.Errorf("unknown output format %q (expected text or json)", yttFormat)
+		}
 	},
 }
+
+func init() {
+	yttCmd.Flags().StringSliceVarP(&yttLanguages, "lang", "l", []string{"en"}, "Preferred transcript language codes, in order of preference")
+	yttCmd.Flags().StringVarP(&yttFormat, "format", "f", "text", "Output format: text or json, or csv with --timeline")
+	yttCmd.Flags().BoolVar(&yttTimeline, "timeline", false, "Show the speaking rate, gaps and bursts of speech over time")
+	yttCmd.Flags().DurationVar(&yttWindow, "window", 30*time.Second, "Length of the sliding window for --timeline")
+	yttCmd.Flags().DurationVar(&yttStep, "step", 5*time.Second, "Time between the windows of --timeline, at least 0.5s")
+	yttCmd.Flags().IntVar(&yttSummary, "summary", 0, "Show a summary of this many sentences")
+	yttCmd.Flags().DurationVar(&yttMinGap, "min-gap", 3*time.Second, "Shortest pause reported as a gap by --timeline")
+}
+
+// writeSummary prints the sentences of a summary with their timestamps
+func writeSummary(out io.Writer, videoID, format string, s *summary.Summary) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			VideoID string `json:"videoId"`
+			*summary.Summary
+		}{videoID, s})
+	case "text":
+		fmt.Fprintf(out, "Summary of video %s (%d of %d sentences):\n", videoID, len(s.Summary), s.Sentences)
+		for _, sentence := range s.Summary {
+			fmt.Fprintf(out, "[%s] %s\n", formatTimestamp(*sentence.Start), sentence.Text)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
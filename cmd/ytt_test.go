@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestYtt(t *testing.T) {
+	out, err := runCommand(t, "ytt", "ytt", "https://www.youtube.com/watch?v=k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+
+	want := `Transcript for video k82RwXqZHY8:
+Hello and welcome to this video.
+Today we learn some basic Finnish words.
+The word "sana" means word.
+Let's get started!
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestYttLanguagePreference(t *testing.T) {
+	out, err := runCommand(t, "ytt", "ytt", "--lang", "sv,fi", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "tänään opimme suomen sanoja") {
+		t.Errorf("expected the Finnish transcript, got:\n%s", out)
+	}
+}
+
+func TestYttJSON(t *testing.T) {
+	out, err := runCommand(t, "ytt", "ytt", "--format", "json", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+
+	var result struct {
+		VideoID string `json:"videoId"`
+		Entries []struct {
+			Text     string  `json:"text"`
+			Start    float64 `json:"start"`
+			Duration float64 `json:"duration"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if result.VideoID != "k82RwXqZHY8" {
+		t.Errorf("videoId = %q", result.VideoID)
+	}
+	if len(result.Entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(result.Entries))
+	}
+	if result.Entries[1].Start != 2.9 || result.Entries[1].Duration != 3.1 {
+		t.Errorf("unexpected timing for entry 1: %+v", result.Entries[1])
+	}
+}
+
+func TestYttConsentPage(t *testing.T) {
+	out, err := runCommand(t, "ytt_consent", "ytt", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Hello and welcome to this video.") {
+		t.Errorf("expected transcript after consent, got:\n%s", out)
+	}
+}
+
+func TestYttLoginRequired(t *testing.T) {
+	_, err := runCommand(t, "ytt_login", "ytt", "k82RwXqZHY8")
+	if err == nil {
+		t.Fatal("expected an error for an age-restricted video")
+	}
+	if !strings.Contains(err.Error(), "Sign in to confirm your age") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestYttInvalidVideo(t *testing.T) {
+	_, err := runCommand(t, "", "ytt", "https://example.com/video")
+	if err == nil || !strings.Contains(err.Error(), "invalid YouTube URL") {
+		t.Errorf("expected invalid URL error, got %v", err)
+	}
+}
+
+func TestYttTimeline(t *testing.T) {
+	out, err := runCommand(t, "ytt", "ytt", "--timeline", "--window", "4s", "--step", "2s", "--min-gap", "0.5s", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	want := `Timeline for video k82RwXqZHY8 (4s windows every 2s):
+Duration: 11s, 21 words, 126.0 words per minute while speaking
+
+   0:00  ▇█▇▆▆
+         ▁ 0 to █ 138.8 words per minute
+
+Gaps:
+  0:00-0:00    0.5s
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = runCommand(t, "ytt", "ytt", "--timeline", "--window", "4s", "--step", "2s", "--format", "csv", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "start,end,words,words_per_minute\n0,4,8.5,127.3\n") || !strings.HasSuffix(out, "8,10.5,4,96\n") {
+		t.Errorf("unexpected CSV:\n%s", out)
+	}
+
+	_, err = runCommand(t, "", "ytt", "--timeline", "--step", "1us", "k82RwXqZHY8")
+	if err == nil || !strings.Contains(err.Error(), "--step must be at least 500ms") {
+		t.Errorf("expected a step below the minimum to be rejected, got %v", err)
+	}
+}
+
+func TestYttSummary(t *testing.T) {
+	out, err := runCommand(t, "ytt", "ytt", "--summary", "2", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	want := `Summary of video k82RwXqZHY8 (2 of 4 sentences):
+[0:02] Today we learn some basic Finnish words.
+[0:06] The word "sana" means word.
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	// Without a Swedish track the English one is summarised in English
+	out, err = runCommand(t, "ytt", "ytt", "--summary", "2", "--lang", "sv", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	if out != want {
+		t.Errorf("unexpected output with --lang sv:\n%s\nwant:\n%s", out, want)
+	}
+
+	if _, err := runCommand(t, "", "ytt", "--summary", "2", "--timeline", "k82RwXqZHY8"); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected --timeline and --summary to be rejected before fetching, got %v", err)
+	}
+
+	out, err = runCommand(t, "ytt", "ytt", "--summary", "1", "--format", "json", "k82RwXqZHY8")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	var result struct {
+		VideoID   string `json:"videoId"`
+		Sentences int    `json:"sentences"`
+		Summary   []struct {
+			Index int     `json:"index"`
+			Start float64 `json:"start"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if result.VideoID != "k82RwXqZHY8" || result.Sentences != 4 || len(result.Summary) != 1 {
+		t.Errorf("unexpected summary: %+v", result)
+	}
+
+	if _, err := runCommand(t, "ytt", "ytt", "--summary", "2", "--timeline", "k82RwXqZHY8"); err == nil {
+		t.Error("expected --summary and --timeline to be rejected together")
+	}
+}
+
+func TestYttLanguageMismatch(t *testing.T) {
+	out, err := runCommand(t, "ytt_mislabeled", "ytt", "Nd4fT0sA9wE")
+	if err != nil {
+		t.Fatalf("ytt failed: %v\n%s", err, out)
+	}
+	if !strings.HasPrefix(out, "Warning: video Nd4fT0sA9wE is declared as en but looks like fi") {
+		t.Errorf("expected a warning about the mislabeled track, got:\n%s", out)
+	}
+	if !strings.Contains(out, "sauna on suomalaisille tärkeä paikka") {
+		t.Errorf("expected the transcript after the warning, got:\n%s", out)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+// sparkBlocks are the levels of a text sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparklineWidth is the number of windows shown per line
+const sparklineWidth = 60
+
+// writeTimeline prints a timeline as a sparkline, CSV rows or JSON
+func writeTimeline(out io.Writer, videoID, format string, tl *words.Timeline) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			VideoID string `json:"videoId"`
+			*words.Timeline
+		}{videoID, tl})
+	case "csv":
+		w := csv.NewWriter(out)
+		w.Write([]string{"start", "end", "words", "words_per_minute"})
+		for _, p := range tl.Points {
+			w.Write([]string{
+				strconv.FormatFloat(p.Start, 'f', -1, 64),
+				strconv.FormatFloat(p.End, 'f', -1, 64),
+				strconv.FormatFloat(p.Words, 'f', -1, 64),
+				strconv.FormatFloat(p.WordsPerMinute, 'f', -1, 64),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	case "text":
+	default:
+		return fmt.Errorf("unknown output format %q (expected text, csv or json)", format)
+	}
+
+	fmt.Fprintf(out, "Timeline for video %s (%s windows every %s):\n", videoID, formatSeconds(tl.Window), formatSeconds(tl.Step))
+	fmt.Fprintf(out, "Duration: %s, %d words, %.1f words per minute while speaking\n\n",
+		formatSeconds(tl.Duration), tl.Words, tl.WordsPerMinute)
+
+	peak := 0.0
+	for _, p := range tl.Points {
+		peak = math.Max(peak, p.WordsPerMinute)
+	}
+	for i := 0; i < len(tl.Points); i += sparklineWidth {
+		row := tl.Points[i:min(i+sparklineWidth, len(tl.Points))]
+		fmt.Fprintf(out, "%7s  %s\n", formatTimestamp(row[0].Start), sparkline(row, peak))
+	}
+	fmt.Fprintf(out, "%7s  %c 0 to %c %.1f words per minute\n", "", sparkBlocks[0], sparkBlocks[len(sparkBlocks)-1], peak)
+
+	if len(tl.Gaps) > 0 {
+		fmt.Fprintln(out, "\nGaps:")
+		for _, g := range tl.Gaps {
+			fmt.Fprintln(out, strings.TrimRight(fmt.Sprintf("  %s-%s  %5.1fs  %s", formatTimestamp(g.Start), formatTimestamp(g.End), g.Duration, g.Label), " "))
+		}
+	}
+	if len(tl.Bursts) > 0 {
+		fmt.Fprintln(out, "\nBursts:")
+		for _, b := range tl.Bursts {
+			fmt.Fprintf(out, "  %s-%s  up to %.1f words per minute\n", formatTimestamp(b.Start), formatTimestamp(b.End), b.PeakWordsPerMinute)
+		}
+	}
+	return nil
+}
+
+// sparkline draws the speaking rate of each window as a block scaled to the peak
+func sparkline(points []words.TimelinePoint, peak float64) string {
+	var b strings.Builder
+	for _, p := range points {
+		level := 0
+		if peak > 0 {
+			level = int(math.Round(p.WordsPerMinute / peak * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
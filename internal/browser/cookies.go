@@ -0,0 +1,126 @@
+package browser
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// httpOnlyPrefix marks HttpOnly cookies in Netscape-format cookie files
+const httpOnlyPrefix = "#HttpOnly_"
+
+// NewCookieJar creates a cookie jar backed by the public suffix list.
+// If cookiesFile is not empty, the jar is preloaded with its cookies.
+func NewCookieJar(cookiesFile string) (http.CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, fmt.Errorf("error creating cookie jar: %v", err)
+	}
+
+	if cookiesFile == "" {
+		return jar, nil
+	}
+
+	cookies, err := LoadCookiesFile(cookiesFile)
+	if err != nil {
+		return nil, err
+	}
+	AddCookies(jar, cookies)
+
+	return jar, nil
+}
+
+// AddCookies stores cookies in the jar, grouped by the host they belong to
+func AddCookies(jar http.CookieJar, cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		host := strings.TrimPrefix(cookie.Domain, ".")
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		u := &url.URL{Scheme: scheme, Host: host, Path: cookie.Path}
+
+		// Cookies without a leading dot are host-only, which the jar
+		// expects to be expressed by an empty Domain attribute
+		c := *cookie
+		if !strings.HasPrefix(c.Domain, ".") {
+			c.Domain = ""
+		}
+		jar.SetCookies(u, []*http.Cookie{&c})
+	}
+}
+
+// LoadCookiesFile reads cookies from a Netscape-format cookies.txt file,
+// as exported by browser extensions or written by curl and yt-dlp
+func LoadCookiesFile(path string) ([]*http.Cookie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cookies file: %v", err)
+	}
+	defer f.Close()
+
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		// Only trim the line end: a cookie with an empty value ends in a tab
+		line := strings.TrimLeft(strings.TrimRight(scanner.Text(), "\r"), " ")
+
+		httpOnly := false
+		if strings.HasPrefix(line, httpOnlyPrefix) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		}
+
+		// Skip comments and blank lines
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookie on line %d of %s: expected 7 tab-separated fields, got %d", lineNum, path, len(fields))
+		}
+
+		domain := fields[0]
+		includeSubdomains := strings.EqualFold(fields[1], "TRUE")
+		if includeSubdomains && !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+
+		cookie := &http.Cookie{
+			Domain:   domain,
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+
+		// An expiry of 0 marks a session cookie
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry on line %d of %s: %v", lineNum, path, err)
+		}
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cookies file: %v", err)
+	}
+
+	return cookies, nil
+}
@@ -0,0 +1,148 @@
+package browser
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCookiesFile writes the given lines to a cookies.txt file
+func writeCookiesFile(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCookiesFile(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want http.Cookie
+	}{
+		{
+			"host only",
+			"www.youtube.com\tFALSE\t/\tFALSE\t0\tPREF\tf1=1",
+			http.Cookie{Domain: "www.youtube.com", Path: "/", Name: "PREF", Value: "f1=1"},
+		},
+		{
+			"leading dot with subdomains",
+			".youtube.com\tTRUE\t/\tTRUE\t2000000000\tSID\tabc",
+			http.Cookie{Domain: ".youtube.com", Path: "/", Secure: true, Name: "SID", Value: "abc", Expires: time.Unix(2000000000, 0)},
+		},
+		{
+			"subdomains without leading dot",
+			"youtube.com\tTRUE\t/feed\tFALSE\t0\tVISITOR\tx",
+			http.Cookie{Domain: ".youtube.com", Path: "/feed", Name: "VISITOR", Value: "x"},
+		},
+		{
+			"HttpOnly prefix",
+			"#HttpOnly_.youtube.com\tTRUE\t/\tTRUE\t0\tHSID\tsecret",
+			http.Cookie{Domain: ".youtube.com", Path: "/", Secure: true, HttpOnly: true, Name: "HSID", Value: "secret"},
+		},
+		{
+			"past expiry",
+			"www.youtube.com\tFALSE\t/\tFALSE\t1\tOLD\tgone",
+			http.Cookie{Domain: "www.youtube.com", Path: "/", Name: "OLD", Value: "gone", Expires: time.Unix(1, 0)},
+		},
+		{
+			"Windows line ending",
+			"www.youtube.com\tFALSE\t/\tFALSE\t0\tPREF\tf1=1\r",
+			http.Cookie{Domain: "www.youtube.com", Path: "/", Name: "PREF", Value: "f1=1"},
+		},
+		{
+			"empty value",
+			"www.youtube.com\tFALSE\t/\tFALSE\t0\tEMPTY\t",
+			http.Cookie{Domain: "www.youtube.com", Path: "/", Name: "EMPTY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies, err := LoadCookiesFile(writeCookiesFile(t, "# Netscape HTTP Cookie File", "", tt.line))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cookies) != 1 {
+				t.Fatalf("got %d cookies, want 1", len(cookies))
+			}
+			got := *cookies[0]
+			if got.Domain != tt.want.Domain || got.Path != tt.want.Path || got.Secure != tt.want.Secure ||
+				got.HttpOnly != tt.want.HttpOnly || got.Name != tt.want.Name || got.Value != tt.want.Value ||
+				!got.Expires.Equal(tt.want.Expires) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCookiesFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"short line", "www.youtube.com\tFALSE\t/\tFALSE\t0\tPREF", "line 2"},
+		{"spaces instead of tabs", "www.youtube.com FALSE / FALSE 0 PREF f1", "expected 7 tab-separated fields"},
+		{"extra field", "www.youtube.com\tFALSE\t/\tFALSE\t0\tPREF\tf1\textra", "got 8"},
+		{"bad expiry", "www.youtube.com\tFALSE\t/\tFALSE\tnever\tPREF\tf1", "invalid expiry on line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCookiesFile(writeCookiesFile(t, "# comment", tt.line))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := LoadCookiesFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestNewCookieJar(t *testing.T) {
+	path := writeCookiesFile(t,
+		"# Comments and blank lines are skipped",
+		"",
+		".youtube.com\tTRUE\t/\tTRUE\t0\tSESSION\tsession",
+		"#HttpOnly_.youtube.com\tTRUE\t/\tTRUE\t4102444800\tLOGIN\tfuture",
+		".youtube.com\tTRUE\t/\tTRUE\t1\tEXPIRED\tpast",
+		"www.youtube.com\tFALSE\t/\tFALSE\t0\tHOST\tonly",
+	)
+	jar, err := NewCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(rawURL string) string {
+		u, _ := url.Parse(rawURL)
+		var names []string
+		for _, c := range jar.Cookies(u) {
+			names = append(names, c.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	// Expired cookies are dropped, host-only cookies stay on their host and
+	// secure cookies are only sent over HTTPS
+	if got := names("https://www.youtube.com/watch"); got != "SESSION,LOGIN,HOST" {
+		t.Errorf("cookies for www.youtube.com = %s", got)
+	}
+	if got := names("https://m.youtube.com/"); got != "SESSION,LOGIN" {
+		t.Errorf("cookies for m.youtube.com = %s", got)
+	}
+	if got := names("http://www.youtube.com/"); got != "HOST" {
+		t.Errorf("cookies over HTTP = %s", got)
+	}
+
+	if _, err := NewCookieJar(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing cookies file")
+	}
+}
@@ -2,24 +2,21 @@ package browser
 
 import "net/http"
 
-// DefaultHeaders returns a map of default headers used for HTTP requests
+// DefaultHeaders returns a map of default headers used for HTTP requests,
+// taken from the default browser profile
 func DefaultHeaders() map[string]string {
-	return map[string]string{
-		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-		"Connection":      "keep-alive",
-		"Cache-Control":   "max-age=0",
+	headers := make(map[string]string)
+	for _, h := range DefaultProfile().Headers {
+		headers[h.Name] = h.Value
 	}
+	return headers
 }
 
 // SetDefaultHeaders sets default headers on the given request
 // If customHeaders is provided, they will override the default values
 func SetDefaultHeaders(req *http.Request, customHeaders ...map[string]string) {
 	// Set default headers
-	for key, value := range DefaultHeaders() {
-		req.Header.Set(key, value)
-	}
+	DefaultProfile().Apply(req)
 
 	// If custom headers are provided, override defaults
 	if len(customHeaders) > 0 {
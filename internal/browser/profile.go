@@ -0,0 +1,213 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Header is a single HTTP header as sent by a browser
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Profile is a coherent set of headers identifying one browser on one platform.
+// Headers are listed in the order the browser sends them, for reference only:
+// request headers are a map and net/http writes them sorted by name, so the
+// order is not reproduced on the wire. Doing so would need our own HTTP/1.1
+// and HTTP/2 framing, which is out of scope.
+//
+// Profiles deliberately omit Accept-Encoding so that net/http keeps handling
+// transparent gzip decompression.
+type Profile struct {
+	Name    string   `json:"name"`
+	Headers []Header `json:"headers"`
+}
+
+// Apply sets the profile's headers on the request, replacing any with the same name
+func (p Profile) Apply(req *http.Request) {
+	for _, h := range p.Headers {
+		req.Header.Set(h.Name, h.Value)
+	}
+}
+
+// Fill sets the headers of the profile that the request does not have yet
+func (p Profile) Fill(req *http.Request) {
+	for _, h := range p.Headers {
+		if req.Header.Get(h.Name) == "" {
+			req.Header.Set(h.Name, h.Value)
+		}
+	}
+}
+
+// Get returns the value of the named header in the profile
+func (p Profile) Get(name string) string {
+	for _, h := range p.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// DefaultProfileName is the profile used when none is selected
+const DefaultProfileName = "chrome-windows"
+
+const (
+	chromeVersion  = "131"
+	firefoxVersion = "133.0"
+	safariVersion  = "18.1"
+
+	chromeSecCHUA = `"Google Chrome";v="` + chromeVersion + `", "Chromium";v="` + chromeVersion + `", "Not_A Brand";v="24"`
+	chromeAccept  = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	firefoxAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	safariAccept  = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+)
+
+func chromeProfile(name, userAgent, platform string, mobile bool) Profile {
+	mobileHint := "?0"
+	if mobile {
+		mobileHint = "?1"
+	}
+	return Profile{
+		Name: name,
+		Headers: []Header{
+			{"Cache-Control", "max-age=0"},
+			{"Sec-CH-UA", chromeSecCHUA},
+			{"Sec-CH-UA-Mobile", mobileHint},
+			{"Sec-CH-UA-Platform", `"` + platform + `"`},
+			{"Upgrade-Insecure-Requests", "1"},
+			{"User-Agent", userAgent},
+			{"Accept", chromeAccept},
+			{"Sec-Fetch-Site", "none"},
+			{"Sec-Fetch-Mode", "navigate"},
+			{"Sec-Fetch-User", "?1"},
+			{"Sec-Fetch-Dest", "document"},
+			{"Accept-Language", "en-US,en;q=0.9"},
+		},
+	}
+}
+
+func firefoxProfile(name, userAgent string) Profile {
+	return Profile{
+		Name: name,
+		Headers: []Header{
+			{"User-Agent", userAgent},
+			{"Accept", firefoxAccept},
+			{"Accept-Language", "en-US,en;q=0.5"},
+			{"Upgrade-Insecure-Requests", "1"},
+			{"Sec-Fetch-Dest", "document"},
+			{"Sec-Fetch-Mode", "navigate"},
+			{"Sec-Fetch-Site", "none"},
+			{"Sec-Fetch-User", "?1"},
+			{"Priority", "u=0, i"},
+		},
+	}
+}
+
+func safariProfile(name, userAgent string) Profile {
+	return Profile{
+		Name: name,
+		Headers: []Header{
+			{"Accept", safariAccept},
+			{"Sec-Fetch-Site", "none"},
+			{"Sec-Fetch-Mode", "navigate"},
+			{"User-Agent", userAgent},
+			{"Accept-Language", "en-US,en;q=0.9"},
+			{"Sec-Fetch-Dest", "document"},
+		},
+	}
+}
+
+// builtinProfiles are the profiles shipped with sanoja
+var builtinProfiles = []Profile{
+	chromeProfile(DefaultProfileName,
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/"+chromeVersion+".0.0.0 Safari/537.36",
+		"Windows", false),
+	chromeProfile("chrome-mac",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/"+chromeVersion+".0.0.0 Safari/537.36",
+		"macOS", false),
+	chromeProfile("chrome-linux",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/"+chromeVersion+".0.0.0 Safari/537.36",
+		"Linux", false),
+	chromeProfile("chrome-android",
+		"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/"+chromeVersion+".0.0.0 Mobile Safari/537.36",
+		"Android", true),
+	firefoxProfile("firefox-windows",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:"+firefoxVersion+") Gecko/20100101 Firefox/"+firefoxVersion),
+	firefoxProfile("firefox-mac",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:"+firefoxVersion+") Gecko/20100101 Firefox/"+firefoxVersion),
+	firefoxProfile("firefox-linux",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:"+firefoxVersion+") Gecko/20100101 Firefox/"+firefoxVersion),
+	firefoxProfile("firefox-android",
+		"Mozilla/5.0 (Android 14; Mobile; rv:"+firefoxVersion+") Gecko/"+firefoxVersion+" Firefox/"+firefoxVersion),
+	safariProfile("safari-mac",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/"+safariVersion+" Safari/605.1.15"),
+	safariProfile("safari-ios",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 18_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/"+safariVersion+" Mobile/15E148 Safari/604.1"),
+}
+
+// Profiles returns the built-in browser profiles
+func Profiles() []Profile {
+	return append([]Profile(nil), builtinProfiles...)
+}
+
+// ProfileNames returns the sorted names of the built-in profiles
+func ProfileNames() []string {
+	var names []string
+	for _, p := range builtinProfiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the built-in profile with the given name
+func LookupProfile(name string) (Profile, bool) {
+	for _, p := range builtinProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// DefaultProfile returns the profile used when none is selected
+func DefaultProfile() Profile {
+	p, _ := LookupProfile(DefaultProfileName)
+	return p
+}
+
+// RandomProfile returns a randomly chosen built-in profile
+func RandomProfile() Profile {
+	return builtinProfiles[rand.IntN(len(builtinProfiles))]
+}
+
+// LoadProfile reads a custom profile from a JSON file of the form
+//
+//	{"name": "my-browser", "headers": [{"name": "User-Agent", "value": "..."}]}
+func LoadProfile(path string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("error reading profile file: %v", err)
+	}
+
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Profile{}, fmt.Errorf("error parsing profile file %s: %v", path, err)
+	}
+
+	if p.Get("User-Agent") == "" {
+		return Profile{}, fmt.Errorf("profile file %s does not set a User-Agent header", path)
+	}
+	if p.Name == "" {
+		p.Name = path
+	}
+
+	return p, nil
+}
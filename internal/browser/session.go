@@ -0,0 +1,85 @@
+package browser
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Profile selection modes accepted by NewSession in addition to profile names
+const (
+	// SelectRandom picks a new random profile for every request
+	SelectRandom = "random"
+	// SelectSticky picks a random profile once and keeps it for the whole session
+	SelectSticky = "sticky"
+)
+
+// Session decides which browser profile is presented on each request.
+// A Session is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	profile Profile
+	rotate  bool
+}
+
+// NewSession creates a session from a profile spec, which is one of:
+//   - "" for the default profile
+//   - the name of a built-in profile, e.g. "firefox-linux"
+//   - "random" to rotate through built-in profiles on every request
+//   - "sticky" to pick a random built-in profile once per session
+//   - the path to a custom JSON profile file
+func NewSession(spec string) (*Session, error) {
+	switch spec {
+	case "":
+		return &Session{profile: DefaultProfile()}, nil
+	case SelectRandom:
+		return &Session{profile: RandomProfile(), rotate: true}, nil
+	case SelectSticky:
+		return &Session{profile: RandomProfile()}, nil
+	}
+
+	if p, ok := LookupProfile(spec); ok {
+		return &Session{profile: p}, nil
+	}
+
+	if _, err := os.Stat(spec); err == nil {
+		p, err := LoadProfile(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &Session{profile: p}, nil
+	}
+
+	return nil, fmt.Errorf("unknown browser profile %q (available: %s, %s, %s, or a profile file)",
+		spec, strings.Join(ProfileNames(), ", "), SelectRandom, SelectSticky)
+}
+
+// NewProfileSession creates a session that always presents the given profile
+func NewProfileSession(p Profile) *Session {
+	return &Session{profile: p}
+}
+
+// Profile returns the profile to use for the next request
+func (s *Session) Profile() Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rotate {
+		s.profile = RandomProfile()
+	}
+	return s.profile
+}
+
+// Apply sets the headers of the session's next profile on the request.
+// Headers already present on the request are overwritten.
+func (s *Session) Apply(req *http.Request) {
+	s.Profile().Apply(req)
+}
+
+// Fill sets the headers of the session's profile that the request does not
+// have yet
+func (s *Session) Fill(req *http.Request) {
+	s.Profile().Fill(req)
+}
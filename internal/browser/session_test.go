@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProfile writes a JSON profile file
+func writeProfile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfile(t, `{"headers": [{"name": "User-Agent", "value": "test-agent"}, {"name": "Accept-Language", "value": "fi"}]}`)
+	p, err := LoadProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != path || p.Get("user-agent") != "test-agent" || p.Get("Accept-Language") != "fi" {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+
+	tests := map[string]string{
+		"{":                            "error parsing profile file",
+		`{"name": "x", "headers": []}`: "does not set a User-Agent",
+	}
+	for content, want := range tests {
+		if _, err := LoadProfile(writeProfile(t, content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadProfile(%s) = %v, want an error containing %q", content, err, want)
+		}
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	s, err := NewSession("")
+	if err != nil || s.Profile().Name != DefaultProfileName {
+		t.Errorf("default session = %v, %v", s, err)
+	}
+
+	s, err = NewSession("firefox-linux")
+	if err != nil || s.Profile().Name != "firefox-linux" {
+		t.Errorf("named session = %v, %v", s, err)
+	}
+
+	s, err = NewSession(writeProfile(t, `{"name": "custom", "headers": [{"name": "User-Agent", "value": "test-agent"}]}`))
+	if err != nil || s.Profile().Name != "custom" {
+		t.Errorf("file session = %v, %v", s, err)
+	}
+
+	if _, err := NewSession("netscape-navigator"); err == nil || !strings.Contains(err.Error(), "unknown browser profile") {
+		t.Errorf("expected an unknown profile to be rejected, got %v", err)
+	}
+	if _, err := NewSession(writeProfile(t, "{")); err == nil {
+		t.Error("expected an invalid profile file to be rejected")
+	}
+}
+
+func TestNewSessionSelection(t *testing.T) {
+	// With ten profiles, fifty random picks all being the same is
+	// vanishingly unlikely
+	const picks = 50
+
+	sticky, err := NewSession(SelectSticky)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := sticky.Profile().Name
+	for i := 0; i < picks; i++ {
+		if name := sticky.Profile().Name; name != first {
+			t.Fatalf("sticky session changed profile from %s to %s", first, name)
+		}
+	}
+
+	random, err := NewSession(SelectRandom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < picks; i++ {
+		name := random.Profile().Name
+		if _, ok := LookupProfile(name); !ok {
+			t.Fatalf("random session picked unknown profile %s", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("random session always picked %v", seen)
+	}
+}
+
+func TestSessionFill(t *testing.T) {
+	s := NewProfileSession(Profile{Name: "test", Headers: []Header{{"User-Agent", "test-agent"}, {"Accept", "text/html"}}})
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	req.Header.Set("Accept", "application/json")
+
+	s.Fill(req)
+	if req.Header.Get("User-Agent") != "test-agent" || req.Header.Get("Accept") != "application/json" {
+		t.Errorf("Fill() = %v, want the missing User-Agent added and Accept kept", req.Header)
+	}
+
+	s.Apply(req)
+	if req.Header.Get("Accept") != "text/html" {
+		t.Errorf("Apply() = %v, want the profile's Accept", req.Header)
+	}
+}
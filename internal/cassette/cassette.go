@@ -0,0 +1,377 @@
+// Package cassette provides an http.RoundTripper that records HTTP interactions
+// to disk and replays them later, so that code talking to live sites can be
+// exercised deterministically and offline.
+//
+// A cassette is a directory holding one JSON file per interaction. In record
+// mode every request is sent upstream and the interaction is written to the
+// cassette; in replay mode requests are answered from the cassette and never
+// leave the process.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeRecord sends requests upstream and saves the interactions
+	ModeRecord Mode = iota
+	// ModeReplay answers requests from saved interactions only
+	ModeReplay
+)
+
+// ScrubbedValue replaces the values of scrubbed headers in saved interactions
+const ScrubbedValue = "[scrubbed]"
+
+// DefaultScrubHeaders are the headers whose values are never written to disk
+var DefaultScrubHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// ErrNoInteraction is returned in replay mode when no saved interaction matches a request
+type ErrNoInteraction struct {
+	Method string
+	URL    string
+}
+
+func (e ErrNoInteraction) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s %s", e.Method, e.URL)
+}
+
+// Request is the saved form of an HTTP request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is the saved form of an HTTP response. Bodies that are not valid
+// UTF-8 are stored base64-encoded in BodyBase64 instead of Body.
+type Response struct {
+	StatusCode int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"bodyBase64,omitempty"`
+}
+
+// Interaction is a request together with the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+
+	used bool
+}
+
+// Matcher reports whether a live request matches a saved one
+type Matcher func(req *http.Request, body []byte, saved Request) bool
+
+// MatchMethod matches requests with the same HTTP method
+func MatchMethod(req *http.Request, body []byte, saved Request) bool {
+	return req.Method == saved.Method
+}
+
+// MatchURL matches requests with the same URL, including the query string
+func MatchURL(req *http.Request, body []byte, saved Request) bool {
+	return req.URL.String() == saved.URL
+}
+
+// MatchBody matches requests with the same body
+func MatchBody(req *http.Request, body []byte, saved Request) bool {
+	return string(body) == saved.Body
+}
+
+// MatchURLIgnoringQuery matches requests with the same URL, disregarding
+// the given query parameters, which is useful for timestamps and nonces
+func MatchURLIgnoringQuery(params ...string) Matcher {
+	strip := func(rawURL string) string {
+		u, err := http.NewRequest("GET", rawURL, nil)
+		if err != nil {
+			return rawURL
+		}
+		query := u.URL.Query()
+		for _, p := range params {
+			query.Del(p)
+		}
+		u.URL.RawQuery = query.Encode()
+		return u.URL.String()
+	}
+	return func(req *http.Request, body []byte, saved Request) bool {
+		return strip(req.URL.String()) == strip(saved.URL)
+	}
+}
+
+// DefaultMatchers match requests on method and full URL
+var DefaultMatchers = []Matcher{MatchMethod, MatchURL}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+type Recorder struct {
+	dir      string
+	mode     Mode
+	next     http.RoundTripper
+	matchers []Matcher
+	scrub    []string
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	recorded     int
+}
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to reach upstream in record mode
+func WithTransport(next http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.next = next
+	}
+}
+
+// WithMatchers replaces the rules deciding which saved interaction answers a request
+func WithMatchers(matchers ...Matcher) Option {
+	return func(r *Recorder) {
+		r.matchers = matchers
+	}
+}
+
+// WithScrubHeaders adds headers whose values are replaced before saving
+func WithScrubHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.scrub = append(r.scrub, names...)
+	}
+}
+
+// New creates a Recorder for the cassette in dir. In replay mode the cassette
+// is loaded immediately; in record mode the directory is created if needed.
+func New(dir string, mode Mode, options ...Option) (*Recorder, error) {
+	r := &Recorder{
+		dir:      dir,
+		mode:     mode,
+		next:     http.DefaultTransport,
+		matchers: DefaultMatchers,
+		scrub:    append([]string(nil), DefaultScrubHeaders...),
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeReplay:
+		interactions, err := Load(dir)
+		if err != nil {
+			return nil, err
+		}
+		r.interactions = interactions
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating cassette directory: %v", err)
+		}
+		// Continue numbering after interactions already in the cassette
+		existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		r.recorded = len(existing)
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %d", mode)
+	}
+
+	return r, nil
+}
+
+// Load reads all interactions saved in a cassette directory, in file name order
+func Load(dir string) ([]*Interaction, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no interactions found in cassette %s", dir)
+	}
+	sort.Strings(files)
+
+	var interactions []*Interaction
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cassette: %v", err)
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("error parsing interaction %s: %v", file, err)
+		}
+		interactions = append(interactions, &interaction)
+	}
+	return interactions, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Prefer interactions not yet replayed so that repeated requests get
+	// their responses in recorded order, then fall back to reusing the last one
+	var match *Interaction
+	for _, interaction := range r.interactions {
+		if r.matches(req, body, interaction.Request) {
+			match = interaction
+			if !interaction.used {
+				break
+			}
+		}
+	}
+	if match == nil {
+		return nil, ErrNoInteraction{Method: req.Method, URL: req.URL.String()}
+	}
+	match.used = true
+
+	return match.Response.toHTTP(req)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: r.scrubHeaders(req.Header),
+			Body:    string(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.scrubHeaders(resp.Header),
+		},
+	}
+	if utf8.Valid(respBody) {
+		interaction.Response.Body = string(respBody)
+	} else {
+		interaction.Response.BodyBase64 = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	if err := r.save(req, &interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// save writes an interaction to a numbered file named after the request
+func (r *Recorder) save(req *http.Request, interaction *Interaction) error {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding interaction: %v", err)
+	}
+
+	r.mu.Lock()
+	r.recorded++
+	seq := r.recorded
+	r.mu.Unlock()
+
+	name := unsafeChars.ReplaceAllString(req.URL.Host+req.URL.Path, "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	file := filepath.Join(r.dir, fmt.Sprintf("%03d_%s_%s.json", seq, strings.ToLower(req.Method), name))
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing interaction: %v", err)
+	}
+	return nil
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, saved Request) bool {
+	for _, match := range r.matchers {
+		if !match(req, body, saved) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Recorder) scrubHeaders(headers http.Header) http.Header {
+	scrubbed := headers.Clone()
+	for _, name := range r.scrub {
+		if _, ok := scrubbed[http.CanonicalHeaderKey(name)]; ok {
+			scrubbed.Set(name, ScrubbedValue)
+		}
+	}
+	return scrubbed
+}
+
+// readBody reads the request body and restores it so it can be sent upstream
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (resp Response) toHTTP(req *http.Request) (*http.Response, error) {
+	body := []byte(resp.Body)
+	if resp.BodyBase64 != "" {
+		decoded, err := base64.StdEncoding.DecodeString(resp.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding recorded body: %v", err)
+		}
+		body = decoded
+	}
+
+	headers := resp.Headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
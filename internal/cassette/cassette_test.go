@@ -0,0 +1,120 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		io.WriteString(w, "hello "+r.URL.Query().Get("name"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := New(dir, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	req, _ := http.NewRequest("GET", server.URL+"/greet?name=world", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	if body := get(t, client, req); body != "hello world" {
+		t.Fatalf("unexpected recorded body %q", body)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 interaction file, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "Bearer token") {
+		t.Errorf("sensitive headers were not scrubbed:\n%s", data)
+	}
+
+	server.Close()
+
+	replayer, err := New(dir, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replayer}
+
+	req, _ = http.NewRequest("GET", server.URL+"/greet?name=world", nil)
+	if body := get(t, client, req); body != "hello world" {
+		t.Errorf("unexpected replayed body %q", body)
+	}
+	if hits != 1 {
+		t.Errorf("replay reached the server: %d hits", hits)
+	}
+
+	req, _ = http.NewRequest("GET", server.URL+"/greet?name=other", nil)
+	if _, err := client.Do(req); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("expected a replay miss, got %v", err)
+	}
+}
+
+func TestReplayInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeInteraction(t, dir, "001.json", `{"request":{"method":"GET","url":"https://example.com/"},"response":{"status":200,"body":"first"}}`)
+	writeInteraction(t, dir, "002.json", `{"request":{"method":"GET","url":"https://example.com/"},"response":{"status":200,"body":"second"}}`)
+
+	recorder, err := New(dir, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	for _, want := range []string{"first", "second", "second"} {
+		req, _ := http.NewRequest("GET", "https://example.com/", nil)
+		if body := get(t, client, req); body != want {
+			t.Errorf("got %q, want %q", body, want)
+		}
+	}
+}
+
+func TestMatchURLIgnoringQuery(t *testing.T) {
+	dir := t.TempDir()
+	writeInteraction(t, dir, "001.json", `{"request":{"method":"GET","url":"https://example.com/chart?symbol=AAPL&ts=1"},"response":{"status":200,"body":"chart"}}`)
+
+	recorder, err := New(dir, ModeReplay, WithMatchers(MatchMethod, MatchURLIgnoringQuery("ts")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	req, _ := http.NewRequest("GET", "https://example.com/chart?ts=2&symbol=AAPL", nil)
+	if body := get(t, client, req); body != "chart" {
+		t.Errorf("got %q, want chart", body)
+	}
+}
+
+func get(t *testing.T, client *http.Client, req *http.Request) string {
+	t.Helper()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func writeInteraction(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
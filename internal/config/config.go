@@ -0,0 +1,305 @@
+// Package config loads sanoja settings from the config file and environment.
+//
+// Settings are resolved with the following precedence, highest first:
+//
+//  1. command-line flags
+//  2. SANOJA_* environment variables
+//  3. the config file, $XDG_CONFIG_HOME/sanoja/config.{yaml,yml,toml}
+//  4. built-in defaults
+//
+// Keys are dotted paths. Global settings live at the top level (e.g. "proxy"),
+// command settings live in a section named after the command (e.g. "serve.port").
+// The matching environment variable is the key upper-cased with dots and dashes
+// replaced by underscores and prefixed with SANOJA_ (e.g. SANOJA_SERVE_PORT).
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of environment variables read as settings
+const EnvPrefix = "SANOJA_"
+
+// fileNames are the config file names looked up in Dir, in order of preference
+var fileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Config holds the settings read from a config file
+type Config struct {
+	path   string
+	values map[string]any
+}
+
+// Dir returns the directory holding sanoja's configuration
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "sanoja"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine config directory: %v", err)
+	}
+	return filepath.Join(dir, "sanoja"), nil
+}
+
+// Path returns the config file in use: the first existing file in Dir,
+// or config.yaml if none exists yet. SANOJA_CONFIG overrides the lookup.
+func Path() (string, error) {
+	if path := os.Getenv(EnvPrefix + "CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range fileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return filepath.Join(dir, fileNames[0]), nil
+}
+
+// Load reads the config file in use. A missing file yields an empty Config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the given YAML or TOML config file, chosen by extension.
+// A missing file yields an empty Config.
+func LoadFile(path string) (*Config, error) {
+	c := &Config{path: path, values: make(map[string]any)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	if isTOML(path) {
+		err = toml.Unmarshal(data, &c.values)
+	} else {
+		err = yaml.Unmarshal(data, &c.values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+	if c.values == nil {
+		c.values = make(map[string]any)
+	}
+
+	return c, nil
+}
+
+// Path returns the file the config was loaded from
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Get returns the value of a key from the config file
+func (c *Config) Get(key string) (string, bool) {
+	var node any = c.values
+	for _, part := range splitKey(key) {
+		section, ok := node.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		node, ok = lookup(section, part)
+		if !ok {
+			return "", false
+		}
+	}
+	if _, ok := node.(map[string]any); ok {
+		return "", false
+	}
+	return format(node), true
+}
+
+// Lookup resolves a key from the environment, falling back to the config file.
+// The returned source is either the environment variable name or the file path.
+func (c *Config) Lookup(key string) (value string, source string, ok bool) {
+	env := EnvVar(key)
+	if value, ok := os.LookupEnv(env); ok {
+		return value, env, true
+	}
+	if value, ok := c.Get(key); ok {
+		return value, c.path, true
+	}
+	return "", "", false
+}
+
+// Set stores a value in the config, typed by kind: the type name of the
+// flag it configures, such as "int", "bool" or "stringSlice". Numbers and
+// booleans are stored typed, slices as lists and everything else, including
+// durations and unknown kinds, as strings.
+func (c *Config) Set(key, value, kind string) error {
+	parts := splitKey(key)
+	if len(parts) == 0 {
+		return fmt.Errorf("empty config key")
+	}
+	typed, err := parseValue(value, kind)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s: %v", value, key, err)
+	}
+
+	section := c.values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := lookup(section, part)
+		if !ok {
+			next = make(map[string]any)
+			section[part] = next
+		}
+		nextSection, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot set %s: %s is not a section", key, part)
+		}
+		section = nextSection
+	}
+
+	last := parts[len(parts)-1]
+	if existing, ok := lookup(section, last); ok {
+		if _, isSection := existing.(map[string]any); isSection {
+			return fmt.Errorf("cannot set %s: it is a section", key)
+		}
+	}
+	section[last] = typed
+	return nil
+}
+
+// Keys returns all keys set in the config file, sorted
+func (c *Config) Keys() []string {
+	var keys []string
+	var walk func(prefix string, section map[string]any)
+	walk = func(prefix string, section map[string]any) {
+		for name, value := range section {
+			key := name
+			if prefix != "" {
+				key = prefix + "." + name
+			}
+			if sub, ok := value.(map[string]any); ok {
+				walk(key, sub)
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	walk("", c.values)
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the config back to the file it was loaded from
+func (c *Config) Save() error {
+	var buf bytes.Buffer
+	if isTOML(c.path) {
+		if err := toml.NewEncoder(&buf).Encode(c.values); err != nil {
+			return fmt.Errorf("error encoding config: %v", err)
+		}
+	} else {
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(c.values); err != nil {
+			return fmt.Errorf("error encoding config: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+	if err := os.WriteFile(c.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("error writing config file: %v", err)
+	}
+	return nil
+}
+
+// EnvVar returns the environment variable that overrides the given key
+func EnvVar(key string) string {
+	replacer := strings.NewReplacer(".", "_", "-", "_")
+	return EnvPrefix + strings.ToUpper(replacer.Replace(key))
+}
+
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+func splitKey(key string) []string {
+	var parts []string
+	for _, part := range strings.Split(key, ".") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// lookup finds a key in a section, treating dashes and underscores alike
+// so that "max_body_size" in a file matches the --max-body-size flag
+func lookup(section map[string]any, name string) (any, bool) {
+	if value, ok := section[name]; ok {
+		return value, true
+	}
+	normalized := normalize(name)
+	for key, value := range section {
+		if normalize(key) == normalized {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+}
+
+// format renders a config value the way a command-line flag would accept it
+func format(value any) string {
+	switch v := value.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = format(item)
+		}
+		return strings.Join(parts, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseValue converts a value to the type of a flag kind
+func parseValue(value, kind string) (any, error) {
+	switch kind {
+	case "bool":
+		return strconv.ParseBool(value)
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count":
+		return strconv.ParseInt(value, 10, 64)
+	case "float32", "float64":
+		return strconv.ParseFloat(value, 64)
+	case "stringSlice", "stringArray":
+		var items []any
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetSaveLoad(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			t.Setenv("SANOJA_CONFIG", path)
+			c, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				key, value, kind string
+			}{
+				{"proxy", "http://localhost:8080", "string"},
+				{"id.nanoid.alphabet", "0123456789", "string"},
+				{"id.count", "007", "int"},
+				{"serve.port", "8080", "int"},
+				{"stock.json", "true", "bool"},
+				{"ytt.step", "1.5", "float64"},
+				{"ytt.window", "1m30s", "duration"},
+				{"ytt.lang", "fi,en", "stringSlice"},
+				{"unknown.setting", "42", ""},
+			}
+			for _, tt := range tests {
+				if err := c.Set(tt.key, tt.value, tt.kind); err != nil {
+					t.Fatalf("Set(%s) = %v", tt.key, err)
+				}
+			}
+			if err := c.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"id.count": "7"}
+			for _, tt := range tests {
+				if _, ok := want[tt.key]; !ok {
+					want[tt.key] = tt.value
+				}
+				value, source, ok := loaded.Lookup(tt.key)
+				if !ok || value != want[tt.key] || source != path {
+					t.Errorf("Lookup(%s) = %q from %s, want %q from %s", tt.key, value, source, want[tt.key], path)
+				}
+			}
+			if keys := loaded.Keys(); len(keys) != len(tests) {
+				t.Errorf("Keys() = %v, want %d keys", keys, len(tests))
+			}
+		})
+	}
+}
+
+func TestSetTypes(t *testing.T) {
+	c := &Config{values: make(map[string]any)}
+	tests := []struct {
+		value, kind string
+		want        any
+	}{
+		{"0123456789", "string", "0123456789"},
+		{"0123456789", "", "0123456789"},
+		{"true", "string", "true"},
+		{"true", "bool", true},
+		{"8", "int", int64(8)},
+		{"0.5", "float64", 0.5},
+		{"5s", "duration", "5s"},
+		{"fi, en", "stringSlice", []any{"fi", "en"}},
+	}
+	for _, tt := range tests {
+		if err := c.Set("key", tt.value, tt.kind); err != nil {
+			t.Fatalf("Set(%q, %s) = %v", tt.value, tt.kind, err)
+		}
+		if got := c.values["key"]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Set(%q, %s) stored %#v, want %#v", tt.value, tt.kind, got, tt.want)
+		}
+	}
+
+	if err := c.Set("key", "eight", "int"); err == nil {
+		t.Error("expected an invalid int to be rejected")
+	}
+	if err := c.Set("key.sub", "1", "int"); err == nil {
+		t.Error("expected setting a key below a value to be rejected")
+	}
+}
+
+func TestLookupEnvironment(t *testing.T) {
+	c := &Config{path: "config.yaml", values: map[string]any{"serve": map[string]any{"max_body_size": 10}}}
+	if value, source, ok := c.Lookup("serve.max-body-size"); !ok || value != "10" || source != "config.yaml" {
+		t.Errorf("Lookup = %q from %s, want 10 from the file with dashes matching underscores", value, source)
+	}
+
+	t.Setenv("SANOJA_SERVE_MAX_BODY_SIZE", "20")
+	if value, source, ok := c.Lookup("serve.max-body-size"); !ok || value != "20" || source != "SANOJA_SERVE_MAX_BODY_SIZE" {
+		t.Errorf("Lookup = %q from %s, want 20 from the environment", value, source)
+	}
+
+	if _, _, ok := c.Lookup("serve"); ok {
+		t.Error("a section should not be a value")
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	c, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || len(c.Keys()) != 0 {
+		t.Errorf("a missing file should be an empty config, got %v, %v", c.Keys(), err)
+	}
+	if _, err := LoadFile("testdata/invalid.yaml"); err == nil {
+		t.Error("expected an invalid file to be rejected")
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mjlefevre/sanoja/pkg/id"
+)
+
+// maxIDs limits the number of IDs generated by one request
+const maxIDs = 1000
+
+// IDHandler handles ID generation requests
+type IDHandler struct {
+	mu sync.Mutex
+	// generators holds one generator per type for requests without options,
+	// so that ULIDs and Snowflake IDs stay monotonic across requests
+	generators map[string]id.Generator
+}
+
+// NewIDHandler creates a new IDHandler
+func NewIDHandler() *IDHandler {
+	return &IDHandler{generators: make(map[string]id.Generator)}
+}
+
+// idResult is the JSON response of GetIDs
+type idResult struct {
+	Type string   `json:"type"`
+	IDs  []string `json:"ids"`
+}
+
+// GetIDs handles GET /api/v1/ids?type=ulid&n=10. NanoIDs accept size and
+// alphabet, UUIDs accept version, namespace and name.
+func (h *IDHandler) GetIDs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	typ := strings.ToLower(query.Get("type"))
+	if typ == "" {
+		typ = "uuid"
+	}
+
+	n := 1
+	if s := query.Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n < 1 || n > maxIDs {
+			writeJSONError(w, http.StatusBadRequest, "n must be a number between 1 and "+strconv.Itoa(maxIDs))
+			return
+		}
+	}
+
+	opts, custom, err := idOptions(query)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if (opts.Version == 3 || opts.Version == 5) && n > 1 {
+		writeJSONError(w, http.StatusBadRequest, "name-based UUIDs are deterministic, so n must be 1")
+		return
+	}
+
+	gen, err := h.generator(typ, opts, custom)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := idResult{Type: typ, IDs: make([]string, 0, n)}
+	for i := 0; i < n; i++ {
+		s, err := gen.New()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		result.IDs = append(result.IDs, s)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// generator returns the shared generator of a type, or a new one if the
+// request has options of its own
+func (h *IDHandler) generator(typ string, opts id.Options, custom bool) (id.Generator, error) {
+	if custom {
+		return id.New(typ, opts)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if gen, ok := h.generators[typ]; ok {
+		return gen, nil
+	}
+	gen, err := id.New(typ, opts)
+	if err != nil {
+		return nil, err
+	}
+	h.generators[typ] = gen
+	return gen, nil
+}
+
+// idOptions reads generator options from the query and reports whether any were given
+func idOptions(query url.Values) (id.Options, bool, error) {
+	var opts id.Options
+	custom := false
+	for _, key := range []string{"alphabet", "size", "version", "namespace", "name"} {
+		custom = custom || query.Has(key)
+	}
+
+	opts.Alphabet = query.Get("alphabet")
+	if s := query.Get("size"); s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil {
+			return opts, custom, fmt.Errorf("size must be a number")
+		}
+		opts.Size = size
+	}
+	if s := query.Get("version"); s != "" {
+		version, err := strconv.Atoi(s)
+		if err != nil {
+			return opts, custom, fmt.Errorf("version must be a number")
+		}
+		opts.Version = version
+	}
+	if s := query.Get("namespace"); s != "" {
+		namespace, err := id.ParseNamespace(s)
+		if err != nil {
+			return opts, custom, err
+		}
+		opts.Namespace = namespace
+	}
+	opts.Name = query.Get("name")
+	return opts, custom, nil
+}
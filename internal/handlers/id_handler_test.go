@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+func TestGetIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/ids", NewIDHandler().GetIDs)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var result idResult
+	if status := getJSON(t, server.URL+"/api/v1/ids?type=ulid&n=10", &result); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if result.Type != "ulid" || len(result.IDs) != 10 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	// ULIDs stay ordered across requests since the generator is shared
+	var next idResult
+	getJSON(t, server.URL+"/api/v1/ids?type=ulid&n=10", &next)
+	all := append(result.IDs, next.IDs...)
+	if !sort.StringsAreSorted(all) {
+		t.Errorf("ULIDs from consecutive requests are out of order: %v", all)
+	}
+
+	result = idResult{}
+	getJSON(t, server.URL+"/api/v1/ids?type=nanoid&size=5&alphabet=xyz", &result)
+	if len(result.IDs) != 1 || len(result.IDs[0]) != 5 {
+		t.Errorf("unexpected NanoIDs: %+v", result)
+	}
+
+	result = idResult{}
+	getJSON(t, server.URL+"/api/v1/ids?type=uuid&version=5&namespace=dns&name=example.com", &result)
+	if len(result.IDs) != 1 || result.IDs[0] != "cfbff0d1-9375-5685-968c-48ce8b15ae17" {
+		t.Errorf("unexpected v5 UUID: %+v", result)
+	}
+
+	for _, query := range []string{"type=guid", "n=0", "n=1001", "type=nanoid&size=x", "type=uuid&version=5", "type=uuid&version=5&name=x&n=2"} {
+		var errResult map[string]string
+		if status := getJSON(t, server.URL+"/api/v1/ids?"+query, &errResult); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, status)
+		}
+		if errResult["error"] == "" {
+			t.Errorf("%s: missing error message", query)
+		}
+	}
+}
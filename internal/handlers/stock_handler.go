@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/templates"
+	"github.com/mjlefevre/sanoja/pkg/stock"
+)
+
+const (
+	// maxSymbols limits the number of symbols fetched by one request
+	maxSymbols = 50
+	// quoteConcurrency limits the number of quotes fetched from Yahoo at once
+	quoteConcurrency = 4
+	// defaultRefresh is the dashboard refresh interval in seconds
+	defaultRefresh = 60
+)
+
+// StockHandler handles stock-related HTTP requests
+type StockHandler struct {
+	client     *stock.Client
+	watchlists string
+	cache      *quoteCache
+}
+
+// NewStockHandler creates a new StockHandler. Quotes are cached for ttl;
+// watchlists is the watchlist file the dashboard reads ?list= from.
+func NewStockHandler(client *stock.Client, ttl time.Duration, watchlists string) *StockHandler {
+	return &StockHandler{
+		client:     client,
+		watchlists: watchlists,
+		cache:      newQuoteCache(ttl),
+	}
+}
+
+// stockResult is the JSON form of one symbol in a multi-symbol response
+type stockResult struct {
+	Symbol string       `json:"symbol"`
+	Quote  *stock.Quote `json:"quote,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// GetStock handles GET /api/v1/stocks/{symbol}
+func (h *StockHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	symbols := stock.NormalizeSymbols([]string{r.PathValue("symbol")})
+	if len(symbols) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "missing stock symbol")
+		return
+	}
+
+	result := h.getQuotes(symbols)[0]
+	if result.Err != nil {
+		status := http.StatusBadGateway
+		if errors.As(result.Err, &stock.ErrSymbolNotFound{}) {
+			status = http.StatusNotFound
+		}
+		writeJSONError(w, status, result.Err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result.Quote)
+}
+
+// GetStocks handles GET /api/v1/stocks?symbols=A,B,C. Symbols that fail are
+// reported individually so one bad symbol does not fail the whole response.
+func (h *StockHandler) GetStocks(w http.ResponseWriter, r *http.Request) {
+	symbols := stock.NormalizeSymbols(strings.Split(r.URL.Query().Get("symbols"), ","))
+	if len(symbols) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "missing symbols parameter, e.g. ?symbols=AAPL,MSFT")
+		return
+	}
+	if len(symbols) > maxSymbols {
+		writeJSONError(w, http.StatusBadRequest, "too many symbols, at most "+strconv.Itoa(maxSymbols)+" are allowed")
+		return
+	}
+
+	var results []stockResult
+	for _, r := range h.getQuotes(symbols) {
+		result := stockResult{Symbol: r.Symbol, Quote: r.Quote}
+		if r.Err != nil {
+			result.Error = r.Err.Error()
+		}
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// Dashboard handles GET /stocks, a page that shows the quotes of the symbols
+// given with ?symbols= or of the watchlist given with ?list=, refreshing them
+// every ?refresh= seconds
+func (h *StockHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	symbols := stock.NormalizeSymbols(strings.Split(query.Get("symbols"), ","))
+
+	if name := query.Get("list"); name != "" {
+		lists, err := stock.LoadWatchlists(h.watchlists)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		saved, err := lists.Get(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		symbols = stock.NormalizeSymbols(append(saved, symbols...))
+	}
+
+	refresh := defaultRefresh
+	if value := query.Get("refresh"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 5 {
+			http.Error(w, "refresh must be a number of seconds, at least 5", http.StatusBadRequest)
+			return
+		}
+		refresh = seconds
+	}
+
+	tmpl, err := template.ParseFS(templates.Files, "stocks.html")
+	if err != nil {
+		http.Error(w, "Error loading dashboard template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = tmpl.Execute(w, struct {
+		Symbols []string
+		Refresh int
+	}{Symbols: symbols, Refresh: refresh})
+	if err != nil {
+		http.Error(w, "Error processing template", http.StatusInternalServerError)
+	}
+}
+
+// getQuotes returns quotes from the cache, fetching the missing ones
+func (h *StockHandler) getQuotes(symbols []string) []stock.QuoteResult {
+	results := make([]stock.QuoteResult, len(symbols))
+	var missing []string
+	var missingIndex []int
+
+	for i, symbol := range symbols {
+		if quote, ok := h.cache.get(symbol); ok {
+			results[i] = stock.QuoteResult{Symbol: symbol, Quote: quote}
+			continue
+		}
+		missing = append(missing, symbol)
+		missingIndex = append(missingIndex, i)
+	}
+
+	for i, result := range h.client.GetQuotes(missing, quoteConcurrency) {
+		if result.Err == nil {
+			h.cache.put(result.Symbol, result.Quote)
+		}
+		results[missingIndex[i]] = result
+	}
+
+	return results
+}
+
+// quoteCache keeps quotes in memory for a limited time
+type quoteCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedQuote
+}
+
+type cachedQuote struct {
+	quote   *stock.Quote
+	expires time.Time
+}
+
+func newQuoteCache(ttl time.Duration) *quoteCache {
+	return &quoteCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedQuote),
+	}
+}
+
+func (c *quoteCache) get(symbol string) (*stock.Quote, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[symbol]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, symbol)
+		return nil, false
+	}
+	return entry.quote, true
+}
+
+func (c *quoteCache) put(symbol string, quote *stock.Quote) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[symbol] = cachedQuote{quote: quote, expires: c.now().Add(c.ttl)}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mjlefevre/sanoja/pkg/stock"
+)
+
+// newStockServer serves the API routes backed by a fake Yahoo Finance that
+// knows only AAPL, and returns the number of requests Yahoo received
+func newStockServer(t *testing.T, ttl time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	page, err := os.ReadFile("../../pkg/stock/testdata/quote_aapl.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hits atomic.Int32
+	yahoo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path != "/quote/AAPL" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+	t.Cleanup(yahoo.Close)
+
+	h := NewStockHandler(stock.NewClient(stock.WithBaseURL(yahoo.URL)), ttl, "")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/stocks/{symbol}", h.GetStock)
+	mux.HandleFunc("GET /api/v1/stocks", h.GetStocks)
+	mux.HandleFunc("GET /stocks", h.Dashboard)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("invalid JSON from %s: %v", url, err)
+	}
+	return resp.StatusCode
+}
+
+func TestGetStock(t *testing.T) {
+	server, hits := newStockServer(t, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		var quote stock.Quote
+		if status := getJSON(t, server.URL+"/api/v1/stocks/aapl", &quote); status != http.StatusOK {
+			t.Fatalf("status = %d", status)
+		}
+		if quote.Symbol != "AAPL" || quote.Price != 254.49 {
+			t.Errorf("unexpected quote: %+v", quote)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("expected the quote to be fetched once and then cached, got %d fetches", n)
+	}
+
+	var body map[string]string
+	if status := getJSON(t, server.URL+"/api/v1/stocks/NOPE", &body); status != http.StatusNotFound {
+		t.Errorf("unknown symbol status = %d", status)
+	}
+	if !strings.Contains(body["error"], "NOPE") {
+		t.Errorf("unexpected error body: %v", body)
+	}
+}
+
+func TestGetStocks(t *testing.T) {
+	server, _ := newStockServer(t, 0)
+
+	var results []stockResult
+	if status := getJSON(t, server.URL+"/api/v1/stocks?symbols=aapl,NOPE", &results); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Quote == nil || results[0].Quote.Price != 254.49 {
+		t.Errorf("unexpected AAPL result: %+v", results[0])
+	}
+	if results[1].Symbol != "NOPE" || results[1].Quote != nil || results[1].Error == "" {
+		t.Errorf("unexpected NOPE result: %+v", results[1])
+	}
+
+	var body map[string]string
+	if status := getJSON(t, server.URL+"/api/v1/stocks", &body); status != http.StatusBadRequest {
+		t.Errorf("missing symbols status = %d", status)
+	}
+}
+
+func TestQuoteCacheExpiry(t *testing.T) {
+	c := newQuoteCache(time.Minute)
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.put("AAPL", &stock.Quote{Symbol: "AAPL"})
+	if _, ok := c.get("AAPL"); !ok {
+		t.Fatal("expected a cached quote")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.get("AAPL"); ok {
+		t.Error("expected the quote to expire after the TTL")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	server, _ := newStockServer(t, time.Minute)
+
+	resp, err := http.Get(server.URL + "/stocks?symbols=aapl,msft&refresh=30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`data-symbol="AAPL"`, `data-symbol="MSFT"`, `const refresh =  30 ;`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("expected %q in dashboard page", want)
+		}
+	}
+}
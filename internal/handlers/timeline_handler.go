@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+// timelineResult is the JSON response of GetTimeline
+type timelineResult struct {
+	VideoID string `json:"videoId"`
+	*words.Timeline
+}
+
+// GetTimeline handles GET /api/v1/videos/{id}/timeline?window=30&step=5,
+// returning the speaking rate series of a video for charting. Times are in
+// seconds; lang lists the preferred transcript languages.
+func (h *TranscriptHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	videoID := transcript.ExtractVideoID(r.PathValue("id"))
+	if videoID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid YouTube video ID: "+r.PathValue("id"))
+		return
+	}
+
+	opts, err := timelineOptions(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.client.GetTranscriptWithLanguages(videoID, transcriptLanguages(r.URL.Query()))
+	if err != nil {
+		writeJSONError(w, transcriptErrorStatus(err), err.Error())
+		return
+	}
+
+	tl := words.AnalyzeTimeline(words.NewTranscriptDocument(videoID, entries), opts)
+	writeJSON(w, http.StatusOK, timelineResult{VideoID: videoID, Timeline: tl})
+}
+
+func timelineOptions(query url.Values) (words.TimelineOptions, error) {
+	var opts words.TimelineOptions
+	for name, field := range map[string]*float64{
+		"window": &opts.Window,
+		"step":   &opts.Step,
+		"minGap": &opts.MinGap,
+		"burst":  &opts.BurstFactor,
+	} {
+		s := query.Get(name)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v <= 0 {
+			return opts, fmt.Errorf("%s must be a positive number", name)
+		}
+		*field = v
+	}
+	if opts.Step != 0 && opts.Step < words.MinTimelineStep {
+		return opts, fmt.Errorf("step must be at least %g seconds", words.MinTimelineStep)
+	}
+	return opts, nil
+}
+
+// transcriptLanguages reads the preferred languages from a comma-separated
+// lang parameter, defaulting to English
+func transcriptLanguages(query url.Values) []string {
+	var languages []string
+	for _, lang := range strings.Split(query.Get("lang"), ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 {
+		return []string{"en"}
+	}
+	return languages
+}
+
+// transcriptErrorStatus maps transcript errors to HTTP statuses
+func transcriptErrorStatus(err error) int {
+	switch {
+	case errors.As(err, &transcript.ErrVideoUnavailable{}), errors.As(err, &transcript.ErrNoTranscriptFound{}),
+		errors.As(err, &transcript.ErrTranscriptsDisabled{}):
+		return http.StatusNotFound
+	case errors.As(err, &transcript.ErrLoginRequired{}):
+		return http.StatusForbidden
+	}
+	return http.StatusBadGateway
+}
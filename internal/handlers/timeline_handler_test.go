@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetTimeline(t *testing.T) {
+	server := newTranscriptServer(t)
+
+	var result timelineResult
+	if status := getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8/timeline?window=4&step=2", &result); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if result.VideoID != "k82RwXqZHY8" || result.Words != 21 || result.WordsPerMinute != 126 || result.Window != 4 {
+		t.Errorf("unexpected timeline: %+v", result.Timeline)
+	}
+	if len(result.Points) != 5 || result.Points[4].End != 10.5 {
+		t.Errorf("unexpected points: %+v", result.Points)
+	}
+
+	var failure map[string]string
+	if status := getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8/timeline?window=-1", &failure); status != http.StatusBadRequest || failure["error"] == "" {
+		t.Errorf("expected an invalid window to be rejected, got %d %v", status, failure)
+	}
+	if status := getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8/timeline?step=0.000001", &failure); status != http.StatusBadRequest {
+		t.Errorf("expected a step below the minimum to be rejected, got %d %v", status, failure)
+	}
+}
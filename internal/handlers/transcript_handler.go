@@ -3,13 +3,20 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"net/http"
+	"strconv"
 
 	"github.com/mjlefevre/sanoja/internal/templates"
+	"github.com/mjlefevre/sanoja/pkg/summary"
 	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/words"
 )
 
+// maxSummarySentences limits the length of a summary requested with summary=N
+const maxSummarySentences = 100
+
 // TranscriptHandler handles transcript-related HTTP requests
 type TranscriptHandler struct {
 	client *transcript.Client
@@ -17,9 +24,9 @@ type TranscriptHandler struct {
 }
 
 // NewTranscriptHandler creates a new TranscriptHandler
-func NewTranscriptHandler(port int) *TranscriptHandler {
+func NewTranscriptHandler(port int, client *transcript.Client) *TranscriptHandler {
 	return &TranscriptHandler{
-		client: transcript.NewClient(),
+		client: client,
 		port:   port,
 	}
 }
@@ -67,6 +74,7 @@ Parameters:
   url         - Full YouTube video URL
   videoId     - Alternative to 'v' parameter
   json        - Add this flag to get JSON response
+  summary     - Return a summary of this many sentences with their timestamps
   bookmarklet - Get a bookmarklet for easy transcript fetching from browser
   help        - Show this help message
   
@@ -74,11 +82,13 @@ Examples:
   /ytt?v=k82RwXqZHY8
   /ytt?url=https://www.youtube.com/watch?v=k82RwXqZHY8
   /ytt?v=k82RwXqZHY8&json
+  /ytt?v=k82RwXqZHY8&summary=5
 
 Response Formats:
   - Default: Plain text
   - JSON: Add 'json' parameter or set Accept: application/json header
-    Returns: {"text": "transcript content"}`
+    Returns: {"text": "transcript content"}, or with summary
+    {"sentences": 57, "summary": [{"index": 3, "text": "...", "start": 12.5, "score": 0.04}]}`
 		w.Write([]byte(helpText))
 		return
 	}
@@ -101,16 +111,22 @@ Response Formats:
 		return
 	}
 
+	// Check if JSON format is requested
+	_, wantJSON := r.URL.Query()["json"]
+	wantJSON = wantJSON || r.Header.Get("Accept") == "application/json"
+
+	if s := r.URL.Query().Get("summary"); s != "" {
+		h.getSummary(w, videoID, s, wantJSON)
+		return
+	}
+
 	transcriptText, err := h.client.GetTranscriptString(videoID)
 	if err != nil {
 		http.Error(w, "Error fetching transcript: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Check if JSON format is requested
-	_, wantJSON := r.URL.Query()["json"]
-	acceptHeader := r.Header.Get("Accept")
-	if wantJSON || acceptHeader == "application/json" {
+	if wantJSON {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{
 			"text": transcriptText,
@@ -122,3 +138,31 @@ Response Formats:
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte(transcriptText))
 }
+
+// getSummary responds with the most central sentences of a transcript
+func (h *TranscriptHandler) getSummary(w http.ResponseWriter, videoID, size string, wantJSON bool) {
+	n, err := strconv.Atoi(size)
+	if err != nil || n < 1 || n > maxSummarySentences {
+		http.Error(w, "summary must be a number of sentences between 1 and "+strconv.Itoa(maxSummarySentences), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.client.GetTranscript(videoID)
+	if err != nil {
+		http.Error(w, "Error fetching transcript: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result := summary.Summarize(words.NewTranscriptDocument(videoID, entries), "en", n)
+
+	if wantJSON {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	for _, sentence := range result.Summary {
+		start := int(*sentence.Start)
+		fmt.Fprintf(w, "[%d:%02d] %s\n", start/60, start%60, sentence.Text)
+	}
+}
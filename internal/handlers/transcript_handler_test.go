@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mjlefevre/sanoja/internal/cassette"
+	"github.com/mjlefevre/sanoja/pkg/summary"
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+)
+
+// newTranscriptServer serves the transcript routes from the transcript of
+// video k82RwXqZHY8 recorded for the ytt command
+func newTranscriptServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	recorder, err := cassette.New("../../cmd/testdata/cassettes/ytt", cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewTranscriptHandler(0, transcript.NewClient(transcript.WithHTTPClient(&http.Client{Transport: recorder})))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ytt", h.GetTranscript)
+	mux.HandleFunc("GET /api/v1/videos/{id}", h.GetVideo)
+	mux.HandleFunc("GET /api/v1/videos/{id}/timeline", h.GetTimeline)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetTranscriptSummary(t *testing.T) {
+	server := newTranscriptServer(t)
+
+	var result summary.Summary
+	if status := getJSON(t, server.URL+"/ytt?v=k82RwXqZHY8&summary=2&json", &result); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if result.Sentences != 4 || len(result.Summary) != 2 || result.Summary[0].Start == nil {
+		t.Errorf("unexpected summary: %+v", result)
+	}
+
+	resp, err := http.Get(server.URL + "/ytt?v=k82RwXqZHY8&summary=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	want := "[0:02] Today we learn some basic Finnish words.\n[0:06] The word \"sana\" means word.\n"
+	if string(body) != want {
+		t.Errorf("text summary =\n%s\nwant\n%s", body, want)
+	}
+
+	for _, size := range []string{"none", "0", "101"} {
+		resp, err = http.Get(server.URL + "/ytt?v=k82RwXqZHY8&summary=" + size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected summary=%s to be rejected, got %d", size, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mjlefevre/sanoja/pkg/langid"
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+// defaultKeywords is the number of key phrases in a video response
+const defaultKeywords = 10
+
+// trackInfo describes a transcript track of a video
+type trackInfo struct {
+	LanguageCode string `json:"languageCode"`
+	Language     string `json:"language"`
+	Generated    bool   `json:"generated"`
+}
+
+// videoResult is the JSON response of GetVideo
+type videoResult struct {
+	VideoID string `json:"videoId"`
+	URL     string `json:"url"`
+	// Transcript is the track the analysis is based on
+	Transcript  trackInfo   `json:"transcript"`
+	Transcripts []trackInfo `json:"transcripts"`
+	// Detected is the language identified from the text of the transcript,
+	// and LanguageMismatch reports whether it contradicts the track's label
+	Detected         langid.Result `json:"detected"`
+	LanguageMismatch bool          `json:"languageMismatch"`
+
+	Words          int             `json:"words"`
+	Duration       float64         `json:"duration"`
+	WordsPerMinute float64         `json:"wordsPerMinute"`
+	Keywords       []words.Keyword `json:"keywords"`
+}
+
+// GetVideo handles GET /api/v1/videos/{id}?lang=fi,en&keywords=10, returning
+// the transcript tracks of a video with the word count, speaking rate and key
+// phrases of the preferred one, and the language detected in its text
+func (h *TranscriptHandler) GetVideo(w http.ResponseWriter, r *http.Request) {
+	videoID := transcript.ExtractVideoID(r.PathValue("id"))
+	if videoID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid YouTube video ID: "+r.PathValue("id"))
+		return
+	}
+
+	top := defaultKeywords
+	if s := r.URL.Query().Get("keywords"); s != "" {
+		var err error
+		if top, err = strconv.Atoi(s); err != nil || top < 0 {
+			writeJSONError(w, http.StatusBadRequest, "keywords must be a number, 0 for all")
+			return
+		}
+	}
+
+	vt, err := h.client.GetVideoTranscript(videoID, transcriptLanguages(r.URL.Query()))
+	if err != nil {
+		writeJSONError(w, transcriptErrorStatus(err), err.Error())
+		return
+	}
+
+	doc := words.NewTranscriptDocument(videoID, vt.Entries)
+	doc.Lang = vt.Track.LanguageCode
+	stats := words.ComputeStats(doc, doc.Lang)
+	detected := doc.DetectLanguage()
+
+	result := videoResult{
+		VideoID:          videoID,
+		URL:              doc.Location,
+		Transcript:       newTrackInfo(vt.Track),
+		Detected:         detected,
+		LanguageMismatch: detected.Mismatch(doc.Lang),
+		Words:            stats.Words,
+		Duration:         stats.Duration,
+		WordsPerMinute:   stats.WordsPerMinute,
+		Keywords:         words.ExtractKeywords(doc, doc.Lang, top),
+	}
+	for _, t := range vt.Available {
+		result.Transcripts = append(result.Transcripts, newTrackInfo(t))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func newTrackInfo(t transcript.Transcript) trackInfo {
+	return trackInfo{LanguageCode: t.LanguageCode, Language: t.Language, Generated: t.IsGenerated}
+}
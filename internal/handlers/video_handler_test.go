@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetVideo(t *testing.T) {
+	server := newTranscriptServer(t)
+
+	var result videoResult
+	if status := getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8?keywords=3", &result); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	if result.VideoID != "k82RwXqZHY8" || result.Transcript.LanguageCode != "en" || len(result.Transcripts) != 2 {
+		t.Errorf("unexpected tracks: %+v", result)
+	}
+	if result.Words != 21 || result.WordsPerMinute != 126 {
+		t.Errorf("unexpected counts: %+v", result)
+	}
+	if result.Detected.Language != "en" || !result.Detected.Reliable || result.LanguageMismatch {
+		t.Errorf("expected English to be detected: %+v", result.Detected)
+	}
+	if len(result.Keywords) != 3 || result.Keywords[0].Phrase != "basic finnish words" || *result.Keywords[0].First != 2.9 {
+		t.Errorf("unexpected keywords: %+v", result.Keywords)
+	}
+
+	result = videoResult{}
+	getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8?lang=fi", &result)
+	if result.Transcript.LanguageCode != "fi" || result.Detected.Language != "fi" || len(result.Keywords) == 0 {
+		t.Errorf("expected the Finnish track: %+v", result)
+	}
+
+	var failure map[string]string
+	if status := getJSON(t, server.URL+"/api/v1/videos/k82RwXqZHY8?keywords=many", &failure); status != http.StatusBadRequest {
+		t.Errorf("expected an invalid keyword count to be rejected, got %d %v", status, failure)
+	}
+}
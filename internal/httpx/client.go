@@ -0,0 +1,117 @@
+// Package httpx builds the HTTP clients shared by all sanoja commands.
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/browser"
+	"github.com/mjlefevre/sanoja/internal/cassette"
+)
+
+// Options configures an HTTP client
+type Options struct {
+	// Timeout is the overall time limit for a request, including retries
+	Timeout time.Duration
+	// Retries is the number of times a failed request is retried
+	Retries int
+	// RetryWait is the initial delay between retries, doubled after each attempt
+	RetryWait time.Duration
+	// Proxy is the proxy URL; if empty, the environment's proxy settings are used
+	Proxy string
+	// CookiesFile is an optional Netscape-format cookies.txt file loaded into the jar
+	CookiesFile string
+	// Profile is the browser profile spec, see browser.NewSession
+	Profile string
+	// MaxBodySize limits the size of response bodies in bytes; 0 means no limit
+	MaxBodySize int64
+	// Debug logs every request and response to stderr
+	Debug bool
+	// Record saves every interaction to this cassette directory
+	Record string
+	// Replay answers requests from this cassette directory instead of the network
+	Replay string
+}
+
+// DefaultOptions returns the options used when nothing else is configured
+func DefaultOptions() Options {
+	return Options{
+		Timeout:     30 * time.Second,
+		Retries:     2,
+		RetryWait:   500 * time.Millisecond,
+		MaxBodySize: 20 << 20,
+	}
+}
+
+// New creates an HTTP client with a persistent cookie jar that presents the
+// configured browser profile on every request
+func New(opts Options) (*http.Client, error) {
+	session, err := browser.NewSession(opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	jar, err := browser.NewCookieJar(opts.CookiesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", opts.Proxy, err)
+		}
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var transport http.RoundTripper = base
+	switch {
+	case opts.Record != "" && opts.Replay != "":
+		return nil, fmt.Errorf("cannot record and replay at the same time")
+	case opts.Record != "":
+		transport, err = cassette.New(opts.Record, cassette.ModeRecord, cassette.WithTransport(base))
+	case opts.Replay != "":
+		transport, err = cassette.New(opts.Replay, cassette.ModeReplay)
+		// Replayed responses never change, so retrying them only adds delay
+		opts.Retries = 0
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Innermost first: each attempt is logged, retries happen below the
+	// size limit, and browser headers are set once before anything else
+	if opts.Debug {
+		transport = &debugTransport{next: transport}
+	}
+	if opts.Retries > 0 {
+		transport = &retryTransport{next: transport, retries: opts.Retries, wait: opts.RetryWait}
+	}
+	if opts.MaxBodySize > 0 {
+		transport = &limitTransport{next: transport, limit: opts.MaxBodySize}
+	}
+	transport = &headerTransport{next: transport, session: session}
+
+	return &http.Client{
+		Transport: transport,
+		Jar:       jar,
+		Timeout:   opts.Timeout,
+	}, nil
+}
+
+// headerTransport fills in the browser profile headers on outgoing requests.
+// Headers set by the caller, such as Accept or Content-Type, are kept.
+type headerTransport struct {
+	next    http.RoundTripper
+	session *browser.Session
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	r := req.Clone(req.Context())
+	t.session.Fill(r)
+	return t.next.RoundTrip(r)
+}
@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewOptionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"record and replay", Options{Record: t.TempDir(), Replay: t.TempDir()}, "record and replay"},
+		{"proxy", Options{Proxy: "http://[::1"}, "invalid proxy URL"},
+		{"profile", Options{Profile: "netscape-navigator"}, "unknown browser profile"},
+		{"cookies", Options{CookiesFile: "testdata/missing-cookies.txt"}, "missing-cookies.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch hits {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+			io.WriteString(w, r.UserAgent())
+		default:
+			if c, err := r.Cookie("session"); err != nil || c.Value != "1" {
+				t.Errorf("expected the cookie from the previous response, got %v", err)
+			}
+		}
+	}))
+	defer server.Close()
+
+	opts := DefaultOptions()
+	opts.RetryWait = 0
+	client, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || hits != 2 || !strings.Contains(string(body), "Mozilla/5.0") {
+		t.Errorf("got %d %q after %d requests, want a retried request with browser headers", resp.StatusCode, body, hits)
+	}
+
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
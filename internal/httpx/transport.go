@@ -0,0 +1,185 @@
+package httpx
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/cassette"
+)
+
+// ErrBodyTooLarge is returned when reading a response body beyond the configured limit
+type ErrBodyTooLarge struct {
+	URL   string
+	Limit int64
+}
+
+func (e ErrBodyTooLarge) Error() string {
+	return fmt.Sprintf("response body from %s exceeds %d bytes", e.URL, e.Limit)
+}
+
+// retryTransport retries requests that fail with a network error, a 429 or a
+// 5xx status. Like net/http, it only retries idempotent requests: those with
+// an idempotent method, or with an Idempotency-Key or X-Idempotency-Key
+// header by which the caller declares that sending them twice is safe.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	wait    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait := t.wait
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !retryable(resp, err) || !idempotent(req) || !rewindable(req) {
+			return resp, err
+		}
+
+		delay := wait
+		if resp != nil {
+			if after := retryAfter(resp); after > 0 {
+				delay = after
+			}
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		wait *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// idempotent reports whether a request may be sent more than once
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+// rewindable reports whether the request body can be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// limitTransport caps the number of bytes that can be read from response bodies
+type limitTransport struct {
+	next  http.RoundTripper
+	limit int64
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{body: resp.Body, remaining: t.limit, url: req.URL.String(), limit: t.limit}
+	return resp, nil
+}
+
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	limit     int64
+	url       string
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there is actually more data to read
+		var probe [1]byte
+		if n, _ := b.body.Read(probe[:]); n > 0 {
+			return 0, ErrBodyTooLarge{URL: b.url, Limit: b.limit}
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// debugTransport logs requests and responses, without their bodies and with
+// the credentials in headers such as Cookie and Authorization redacted, as
+// in saved cassettes
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	redacted := req.Clone(req.Context())
+	redacted.Header = redactHeaders(req.Header)
+	if dump, err := httputil.DumpRequestOut(redacted, false); err == nil {
+		log.Printf("> %s", dump)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("< %s %s failed after %v: %v", req.Method, req.URL, time.Since(start), err)
+		return nil, err
+	}
+
+	logged := *resp
+	logged.Header = redactHeaders(resp.Header)
+	if dump, err := httputil.DumpResponse(&logged, false); err == nil {
+		log.Printf("< %s %s (%v)\n%s", req.Method, req.URL, time.Since(start), dump)
+	}
+	return resp, nil
+}
+
+// redactHeaders returns a copy of headers with the values of the headers
+// cassettes scrub replaced
+func redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, name := range cassette.DefaultScrubHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, cassette.ScrubbedValue)
+		}
+	}
+	return redacted
+}
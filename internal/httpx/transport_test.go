@@ -0,0 +1,283 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mjlefevre/sanoja/internal/browser"
+	"github.com/mjlefevre/sanoja/internal/cassette"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// statusServer answers with the given statuses in turn, then with 200 OK
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+		hits     int32
+	}{
+		{"retries server errors", []int{http.StatusServiceUnavailable, http.StatusInternalServerError}, http.StatusOK, 3},
+		{"retries rate limiting", []int{http.StatusTooManyRequests}, http.StatusOK, 2},
+		{"gives up after the retries", []int{500, 502, 503}, http.StatusServiceUnavailable, 3},
+		{"does not retry client errors", []int{http.StatusNotFound}, http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := statusServer(t, tt.statuses...)
+			client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 2, wait: time.Millisecond}}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want || hits.Load() != tt.hits {
+				t.Errorf("got %d after %d requests, want %d after %d", resp.StatusCode, hits.Load(), tt.want, tt.hits)
+			}
+		})
+	}
+}
+
+func TestRetryTransportNetworkError(t *testing.T) {
+	attempts := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	transport := &retryTransport{next: next, retries: 1, wait: time.Millisecond}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Errorf("got %v, %v after %d attempts, want a retried success", resp, err, attempts)
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 1, wait: time.Millisecond}}
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want the Retry-After delay of 1s", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"2":                             2 * time.Second,
+		"":                              0,
+		"0":                             0,
+		"-5":                            0,
+		"Wed, 21 Oct 2015 07:28:00 GMT": 0,
+	}
+	for header, want := range tests {
+		resp := &http.Response{Header: http.Header{"Retry-After": {header}}}
+		if got := retryAfter(resp); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestRetryTransportRewindsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 2, wait: time.Millisecond}}
+
+	// http.NewRequest sets GetBody for a strings.Reader, so the body of a
+	// request declared idempotent is sent again
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader("payload"))
+	req.Header.Set("Idempotency-Key", "1")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Errorf("bodies = %q, want the payload twice", bodies)
+	}
+
+	// A body that cannot be rewound is sent only once
+	bodies = nil
+	req, _ = http.NewRequest("PUT", server.URL, io.NopCloser(strings.NewReader("once")))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 1 || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("bodies = %q, status %d, want a single attempt", bodies, resp.StatusCode)
+	}
+
+	// So is a POST, e.g. a webhook notification, unless declared idempotent
+	bodies = nil
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("notification"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 1 || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("bodies = %q, status %d, want a single attempt", bodies, resp.StatusCode)
+	}
+}
+
+func TestRetryTransportContextCanceled(t *testing.T) {
+	server, _ := statusServer(t, 500, 500)
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 1, wait: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to end the wait between retries, got %v", err)
+	}
+}
+
+func TestLimitTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "0123456789")
+	}))
+	defer server.Close()
+
+	read := func(limit int64) ([]byte, error) {
+		client := &http.Client{Transport: &limitTransport{next: http.DefaultTransport, limit: limit}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	}
+
+	if body, err := read(10); err != nil || string(body) != "0123456789" {
+		t.Errorf("a body of exactly the limit should be read whole, got %q, %v", body, err)
+	}
+
+	body, err := read(4)
+	var tooLarge ErrBodyTooLarge
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 4 {
+		t.Fatalf("expected ErrBodyTooLarge, got %q, %v", body, err)
+	}
+	if string(body) != "0123" {
+		t.Errorf("expected the body up to the limit, got %q", body)
+	}
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	session, err := browser.NewSession("firefox-linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &headerTransport{next: http.DefaultTransport, session: session}}
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	profile, _ := browser.LookupProfile("firefox-linux")
+	if got.Get("User-Agent") != profile.Get("User-Agent") || got.Get("Accept-Language") != profile.Get("Accept-Language") {
+		t.Errorf("User-Agent = %q, Accept-Language = %q, want the profile's", got.Get("User-Agent"), got.Get("Accept-Language"))
+	}
+	for name, want := range map[string]string{"Accept": "application/json", "Cache-Control": "no-cache", "Content-Type": "application/json"} {
+		if got.Get(name) != want {
+			t.Errorf("%s = %q, want the caller's %q kept", name, got.Get(name), want)
+		}
+	}
+	if req.Header.Get("User-Agent") != "" {
+		t.Error("the caller's request was modified")
+	}
+}
+
+func TestDebugTransportRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "response-secret"})
+	}))
+	defer server.Close()
+
+	var logged strings.Builder
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	client := &http.Client{Transport: &debugTransport{next: http.DefaultTransport}}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Cookie", "SID=request-secret")
+	req.Header.Set("Authorization", "Bearer token-secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(logged.String(), "secret") || !strings.Contains(logged.String(), "Cookie: "+cassette.ScrubbedValue) {
+		t.Errorf("expected credentials to be redacted, got:\n%s", logged.String())
+	}
+	if req.Header.Get("Cookie") != "SID=request-secret" || resp.Header.Get("Set-Cookie") == cassette.ScrubbedValue {
+		t.Error("the request or response was modified")
+	}
+}
@@ -0,0 +1,119 @@
+// Package notify delivers notifications to pluggable sinks such as the
+// terminal, a webhook or a desktop notification command.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Notification is a message delivered to a sink
+type Notification struct {
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	// Data is optional structured detail, included in webhook payloads
+	Data any `json:"data,omitempty"`
+}
+
+// Sink delivers notifications
+type Sink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Send delivers a notification to all sinks, continuing past failures
+func Send(ctx context.Context, sinks []Sink, n Notification) error {
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Writer prints notifications as timestamped lines
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a sink that prints to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Notify implements Sink
+func (s *Writer) Notify(ctx context.Context, n Notification) error {
+	_, err := fmt.Fprintf(s.w, "%s %s\n", n.Time.Format("2006-01-02 15:04:05"), n.Message)
+	return err
+}
+
+// Webhook POSTs notifications as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a sink that posts to url using client
+func NewWebhook(client *http.Client, url string) *Webhook {
+	return &Webhook{url: url, client: client}
+}
+
+// Notify implements Sink
+func (s *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Command runs a program for every notification, passing the title and
+// message as the last two arguments, e.g. "notify-send -u critical"
+type Command struct {
+	name string
+	args []string
+}
+
+// NewCommand creates a sink from a command line split on whitespace
+func NewCommand(command string) (*Command, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty notification command")
+	}
+	return &Command{name: fields[0], args: fields[1:]}, nil
+}
+
+// Notify implements Sink
+func (s *Command) Notify(ctx context.Context, n Notification) error {
+	args := append(append([]string(nil), s.args...), n.Title, n.Message)
+	out, err := exec.CommandContext(ctx, s.name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("notification command %s failed: %v: %s", s.name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
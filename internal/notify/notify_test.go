@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	var out strings.Builder
+	n := Notification{
+		Title:   "sanoja: AAPL",
+		Message: "AAPL is above 250",
+		Time:    time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+	sinks := []Sink{
+		NewWebhook(http.DefaultClient, failing.URL),
+		NewWriter(&out),
+		NewWebhook(http.DefaultClient, server.URL),
+	}
+
+	err := Send(context.Background(), sinks, n)
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("expected the failing webhook to be reported, got %v", err)
+	}
+	if out.String() != "2025-01-02 15:04:05 AAPL is above 250\n" {
+		t.Errorf("writer output = %q", out.String())
+	}
+	if received.Message != n.Message || !received.Time.Equal(n.Time) {
+		t.Errorf("webhook received %+v", received)
+	}
+}
+
+func TestNewCommand(t *testing.T) {
+	if _, err := NewCommand("  "); err == nil {
+		t.Error("expected an empty command to be rejected")
+	}
+	c, err := NewCommand("notify-send -u critical")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.name != "notify-send" || strings.Join(c.args, " ") != "-u critical" {
+		t.Errorf("unexpected command %+v", c)
+	}
+}
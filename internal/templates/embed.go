@@ -2,5 +2,5 @@ package templates
 
 import "embed"
 
-//go:embed bookmarklet.html
+//go:embed bookmarklet.html stocks.html
 var Files embed.FS
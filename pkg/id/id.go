@@ -0,0 +1,154 @@
+// Package id generates unique identifiers in several formats: UUIDs, ULIDs,
+// NanoIDs, KSUIDs and Snowflake IDs.
+//
+// Generators are looked up by type name, so new formats can be plugged in
+// with Register:
+//
+//	gen, err := id.New("ulid", id.Options{})
+//	s, err := gen.New()
+//
+// Sortable formats embed their creation time, which their generators expose
+// through the Timestamper interface and Time.
+package id
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generator creates IDs of one format. Generators are safe for concurrent use.
+type Generator interface {
+	New() (string, error)
+}
+
+// Timestamper is implemented by generators whose IDs embed their creation time
+type Timestamper interface {
+	Time(id string) (time.Time, error)
+}
+
+// Options configures generators. Each generator only reads its own fields
+// and uses its defaults for zero values.
+type Options struct {
+	// Alphabet and Size configure NanoIDs
+	Alphabet string
+	Size     int
+
+	// Node and Epoch configure Snowflake IDs
+	Node  int64
+	Epoch time.Time
+
+	// Version selects the UUID version; Namespace and Name are used by
+	// the name-based versions 3 and 5
+	Version   int
+	Namespace uuid.UUID
+	Name      string
+}
+
+// Factory creates a generator from options
+type Factory func(opts Options) (Generator, error)
+
+// ErrUnknownType is returned for ID types that have no registered generator
+type ErrUnknownType struct {
+	Type string
+}
+
+func (e ErrUnknownType) Error() string {
+	return fmt.Sprintf("unknown ID type %q (available: %s)", e.Type, strings.Join(Types(), ", "))
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a generator available under the given type name,
+// replacing any generator registered under the same name
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = factory
+}
+
+// Types returns the sorted names of the registered ID types
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates a generator for the given ID type
+func New(name string, opts Options) (Generator, error) {
+	registryMu.RLock()
+	factory, ok := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownType{Type: name}
+	}
+	return factory(opts)
+}
+
+// Generate creates n IDs of the given type
+func Generate(name string, opts Options, n int) ([]string, error) {
+	gen, err := New(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		id, err := gen.New()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Time extracts the creation time embedded in an ID of the given type
+func Time(name, id string, opts Options) (time.Time, error) {
+	gen, err := New(name, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ts, ok := gen.(Timestamper)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s IDs do not contain a timestamp", name)
+	}
+	return ts.Time(id)
+}
+
+// Detect guesses the type of an ID from its length and alphabet.
+// NanoIDs are random strings and cannot be told apart reliably.
+func Detect(id string) (string, bool) {
+	switch {
+	case isUUID(id):
+		return "uuid", true
+	case len(id) == ulidLength && isULID(id):
+		return "ulid", true
+	case len(id) == ksuidLength && isBase62(id):
+		return "ksuid", true
+	case isSnowflake(id):
+		return "snowflake", true
+	}
+	return "", false
+}
+
+func init() {
+	Register("uuid", newUUIDGenerator)
+	Register("ulid", newULIDGenerator)
+	Register("nanoid", newNanoIDGenerator)
+	Register("ksuid", newKSUIDGenerator)
+	Register("snowflake", newSnowflakeGenerator)
+}
@@ -0,0 +1,161 @@
+package id
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateFormats(t *testing.T) {
+	tests := []struct {
+		typ    string
+		opts   Options
+		length int
+	}{
+		{"uuid", Options{}, 36},
+		{"ulid", Options{}, 26},
+		{"nanoid", Options{}, 21},
+		{"nanoid", Options{Size: 10, Alphabet: "0123456789abcdef"}, 10},
+		{"ksuid", Options{}, 27},
+	}
+	for _, tt := range tests {
+		ids, err := Generate(tt.typ, tt.opts, 100)
+		if err != nil {
+			t.Fatalf("Generate(%s): %v", tt.typ, err)
+		}
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			if len(id) != tt.length {
+				t.Errorf("%s ID %q has length %d, want %d", tt.typ, id, len(id), tt.length)
+			}
+			if seen[id] {
+				t.Errorf("duplicate %s ID %q", tt.typ, id)
+			}
+			seen[id] = true
+		}
+	}
+
+	ids, err := Generate("nanoid", Options{Size: 50, Alphabet: "ab"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Trim(ids[0], "ab") != "" {
+		t.Errorf("NanoID %q uses characters outside its alphabet", ids[0])
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := New("guid", Options{}); !errors.As(err, &ErrUnknownType{}) {
+		t.Errorf("expected ErrUnknownType, got %v", err)
+	}
+	for _, opts := range []Options{{Alphabet: "a"}, {Alphabet: "aba"}, {Size: -1}} {
+		if _, err := New("nanoid", opts); err == nil {
+			t.Errorf("nanoid %+v: expected an error", opts)
+		}
+	}
+	if _, err := New("snowflake", Options{Node: MaxSnowflakeNode + 1}); err == nil {
+		t.Error("expected an error for an out of range Snowflake node")
+	}
+	if _, err := New("uuid", Options{Version: 5}); err == nil {
+		t.Error("expected an error for a v5 UUID without a name")
+	}
+	if _, err := New("uuid", Options{Version: 2}); err == nil {
+		t.Error("expected an error for v2 UUIDs")
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	// A fixed clock puts every ID in the same millisecond
+	now := func() time.Time { return time.UnixMilli(1700000000000) }
+	generators := map[string]Generator{
+		"ulid":      &ulidGenerator{now: now},
+		"snowflake": &snowflakeGenerator{node: 1, epoch: DefaultSnowflakeEpoch, now: now},
+	}
+	for typ, gen := range generators {
+		var ids []string
+		for i := 0; i < 5000; i++ {
+			id, err := gen.New()
+			if err != nil {
+				t.Fatalf("%s: %v", typ, err)
+			}
+			ids = append(ids, id)
+		}
+		if !sort.StringsAreSorted(ids) {
+			t.Errorf("%s IDs from the same millisecond are out of order", typ)
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] == ids[i-1] {
+				t.Fatalf("duplicate %s ID %s", typ, ids[i])
+			}
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		typ  string
+		id   string
+		want string
+	}{
+		{"ulid", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "2016-07-30T23:54:10.259Z"},
+		{"ksuid", "0ujtsYcgvSTl8PAuAdqWYSMnLOv", "2017-10-10T04:00:47Z"},
+		{"ksuid", "aWgEPTl1tmebfsQzFP4bxwgy80V", "2150-06-19T23:21:35Z"},
+		{"snowflake", "1541815603606036480", "2022-06-28T16:07:40.105Z"},
+		{"uuid", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", "2022-02-22T19:22:22Z"},
+		{"uuid", "1ec9414c-232a-6b00-b3c8-9f6bdeced846", "2022-02-22T19:22:22Z"},
+		{"uuid", "c232ab00-9414-11ec-b3c8-9f6bdeced846", "2022-02-22T19:22:22Z"},
+	}
+	for _, tt := range tests {
+		got, err := Time(tt.typ, tt.id, Options{})
+		if err != nil {
+			t.Fatalf("Time(%s, %s): %v", tt.typ, tt.id, err)
+		}
+		if s := got.Format(time.RFC3339Nano); s != tt.want {
+			t.Errorf("Time(%s, %s) = %s, want %s", tt.typ, tt.id, s, tt.want)
+		}
+
+		if typ, ok := Detect(tt.id); !ok || typ != tt.typ {
+			t.Errorf("Detect(%s) = %q, want %q", tt.id, typ, tt.typ)
+		}
+	}
+
+	if _, err := Time("nanoid", "V1StGXR8_Z5jdHi6B-myT", Options{}); err == nil {
+		t.Error("expected an error for NanoIDs, which have no timestamp")
+	}
+	if _, err := Time("uuid", "919108f7-52d1-4320-9bac-f847db4148a8", Options{}); err == nil {
+		t.Error("expected an error for v4 UUIDs, which have no timestamp")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	for _, typ := range []string{"ulid", "ksuid", "snowflake"} {
+		ids, err := Generate(typ, Options{}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, err := Time(typ, ids[0], Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts.Before(start.Truncate(time.Second)) || ts.After(time.Now()) {
+			t.Errorf("%s %s has time %v, want about now", typ, ids[0], ts)
+		}
+	}
+}
+
+func TestNewV6(t *testing.T) {
+	u, err := NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Version() != 6 {
+		t.Errorf("version = %d, want 6", u.Version())
+	}
+	ts, ok := UUIDTime(u)
+	if !ok || time.Since(ts).Abs() > time.Minute {
+		t.Errorf("v6 UUID %s has time %v, want now", u, ts)
+	}
+}
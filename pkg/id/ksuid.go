@@ -0,0 +1,75 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// ksuidLength is the length of a KSUID in base62
+	ksuidLength = 27
+	// ksuidEpoch is the start of KSUID time, 2014-05-13T16:53:20Z
+	ksuidEpoch = 1400000000
+	base62     = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// ksuidGenerator creates KSUIDs: a 32-bit count of seconds since ksuidEpoch
+// followed by 128 random bits, encoded in base62. KSUIDs sort by second.
+type ksuidGenerator struct {
+	now func() time.Time
+}
+
+func newKSUIDGenerator(opts Options) (Generator, error) {
+	return &ksuidGenerator{now: time.Now}, nil
+}
+
+func (g *ksuidGenerator) New() (string, error) {
+	var data [20]byte
+	binary.BigEndian.PutUint32(data[0:4], uint32(g.now().Unix()-ksuidEpoch))
+	if _, err := rand.Read(data[4:]); err != nil {
+		return "", fmt.Errorf("error generating random bits: %v", err)
+	}
+
+	s := swapCase(new(big.Int).SetBytes(data[:]).Text(62))
+	return strings.Repeat("0", ksuidLength-len(s)) + s, nil
+}
+
+func (g *ksuidGenerator) Time(id string) (time.Time, error) {
+	if len(id) != ksuidLength || !isBase62(id) {
+		return time.Time{}, fmt.Errorf("invalid KSUID %q", id)
+	}
+	n, _ := new(big.Int).SetString(swapCase(id), 62)
+	if n.BitLen() > 160 {
+		return time.Time{}, fmt.Errorf("invalid KSUID %q: out of range", id)
+	}
+	data := n.FillBytes(make([]byte, 20))
+	seconds := int64(binary.BigEndian.Uint32(data[0:4])) + ksuidEpoch
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// swapCase converts between big.Int's base62 digits, which put lower case
+// letters first, and the KSUID alphabet, which puts upper case first
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return r
+	}, s)
+}
+
+func isBase62(id string) bool {
+	for _, c := range id {
+		if !strings.ContainsRune(base62, c) {
+			return false
+		}
+	}
+	return id != ""
+}
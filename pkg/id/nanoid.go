@@ -0,0 +1,76 @@
+package id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/bits"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultNanoIDAlphabet is the URL-safe alphabet of the reference NanoID
+	DefaultNanoIDAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+	// DefaultNanoIDSize gives about as many random bits as a v4 UUID
+	DefaultNanoIDSize = 21
+)
+
+// nanoidGenerator creates random IDs of a fixed size drawn from an alphabet.
+// Random bytes are masked to the smallest power of two covering the alphabet
+// and out-of-range values are redrawn, so every character is equally likely.
+type nanoidGenerator struct {
+	alphabet []rune
+	size     int
+	mask     byte
+}
+
+func newNanoIDGenerator(opts Options) (Generator, error) {
+	alphabet := opts.Alphabet
+	if alphabet == "" {
+		alphabet = DefaultNanoIDAlphabet
+	}
+	size := opts.Size
+	if size == 0 {
+		size = DefaultNanoIDSize
+	}
+
+	if !utf8.ValidString(alphabet) {
+		return nil, fmt.Errorf("NanoID alphabet is not valid UTF-8")
+	}
+	runes := []rune(alphabet)
+	if len(runes) < 2 || len(runes) > 256 {
+		return nil, fmt.Errorf("NanoID alphabet must have between 2 and 256 characters, got %d", len(runes))
+	}
+	seen := make(map[rune]bool)
+	for _, r := range runes {
+		if seen[r] {
+			return nil, fmt.Errorf("NanoID alphabet repeats %q", r)
+		}
+		seen[r] = true
+	}
+	if size < 1 || size > 1024 {
+		return nil, fmt.Errorf("NanoID size must be between 1 and 1024, got %d", size)
+	}
+
+	mask := 1<<bits.Len(uint(len(runes)-1)) - 1
+	return &nanoidGenerator{alphabet: runes, size: size, mask: byte(mask)}, nil
+}
+
+func (g *nanoidGenerator) New() (string, error) {
+	id := make([]rune, 0, g.size)
+	// Draw a few more bytes than needed to make up for redrawn values
+	buf := make([]byte, g.size+g.size/2)
+	for len(id) < g.size {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("error generating random bits: %v", err)
+		}
+		for _, b := range buf {
+			if i := int(b & g.mask); i < len(g.alphabet) {
+				id = append(id, g.alphabet[i])
+				if len(id) == g.size {
+					break
+				}
+			}
+		}
+	}
+	return string(id), nil
+}
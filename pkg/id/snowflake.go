@@ -0,0 +1,87 @@
+package id
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	// MaxSnowflakeNode is the highest node number that fits in a Snowflake ID
+	MaxSnowflakeNode = 1<<snowflakeNodeBits - 1
+	maxSequence      = 1<<snowflakeSequenceBits - 1
+)
+
+// DefaultSnowflakeEpoch is the epoch of Twitter's Snowflake IDs
+var DefaultSnowflakeEpoch = time.UnixMilli(1288834974657).UTC()
+
+// snowflakeGenerator creates 63-bit Snowflake IDs: milliseconds since the
+// epoch, then the node number, then a sequence number counting IDs created in
+// the same millisecond. IDs are unique as long as every generator running at
+// the same time has its own node number.
+type snowflakeGenerator struct {
+	node  int64
+	epoch time.Time
+	now   func() time.Time
+
+	mu       sync.Mutex
+	last     int64 // milliseconds since epoch of the previous ID
+	sequence int64
+}
+
+func newSnowflakeGenerator(opts Options) (Generator, error) {
+	if opts.Node < 0 || opts.Node > MaxSnowflakeNode {
+		return nil, fmt.Errorf("Snowflake node must be between 0 and %d, got %d", MaxSnowflakeNode, opts.Node)
+	}
+	epoch := opts.Epoch
+	if epoch.IsZero() {
+		epoch = DefaultSnowflakeEpoch
+	}
+	return &snowflakeGenerator{node: opts.Node, epoch: epoch, now: time.Now}, nil
+}
+
+func (g *snowflakeGenerator) New() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.now().Sub(g.epoch).Milliseconds()
+	if ms < 0 {
+		return "", fmt.Errorf("Snowflake epoch %s is in the future", g.epoch.Format(time.RFC3339))
+	}
+	if ms <= g.last {
+		// Same millisecond, or the clock went back: continue the sequence,
+		// borrowing the next millisecond once it is used up
+		ms = g.last
+		g.sequence++
+		if g.sequence > maxSequence {
+			ms++
+			g.sequence = 0
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.last = ms
+
+	if ms >= 1<<41 {
+		return "", fmt.Errorf("Snowflake timestamp overflowed 41 bits")
+	}
+	id := ms<<(snowflakeNodeBits+snowflakeSequenceBits) | g.node<<snowflakeSequenceBits | g.sequence
+	return strconv.FormatInt(id, 10), nil
+}
+
+func (g *snowflakeGenerator) Time(id string) (time.Time, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("invalid Snowflake ID %q", id)
+	}
+	ms := n >> (snowflakeNodeBits + snowflakeSequenceBits)
+	return g.epoch.Add(time.Duration(ms) * time.Millisecond).UTC(), nil
+}
+
+func isSnowflake(id string) bool {
+	n, err := strconv.ParseInt(id, 10, 64)
+	return err == nil && n >= 0
+}
@@ -0,0 +1,107 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ulidLength is the length of a ULID in Crockford's base32
+	ulidLength = 26
+	// crockford is the base32 alphabet of ULIDs, which leaves out I, L, O and U
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// ulidGenerator creates ULIDs: a 48-bit Unix time in milliseconds followed by
+// 80 random bits. Within one millisecond the random part is incremented
+// instead of redrawn, so IDs from one generator sort in creation order.
+type ulidGenerator struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	last   uint64   // timestamp of the previous ULID in milliseconds
+	random [10]byte // random part of the previous ULID
+}
+
+func newULIDGenerator(opts Options) (Generator, error) {
+	return &ulidGenerator{now: time.Now}, nil
+}
+
+func (g *ulidGenerator) New() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.last {
+		// Same millisecond, or the clock went back: stay monotonic
+		ms = g.last
+		if !increment(g.random[:]) {
+			return "", fmt.Errorf("ULID random part overflowed within one millisecond")
+		}
+	} else {
+		if _, err := rand.Read(g.random[:]); err != nil {
+			return "", fmt.Errorf("error generating random bits: %v", err)
+		}
+		g.last = ms
+	}
+
+	var data [16]byte
+	binary.BigEndian.PutUint16(data[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(data[2:6], uint32(ms))
+	copy(data[6:], g.random[:])
+	return encodeULID(data), nil
+}
+
+func (g *ulidGenerator) Time(id string) (time.Time, error) {
+	if len(id) != ulidLength || !isULID(id) {
+		return time.Time{}, fmt.Errorf("invalid ULID %q", id)
+	}
+	var ms uint64
+	for _, c := range strings.ToUpper(id[:10]) {
+		ms = ms<<5 | uint64(strings.IndexRune(crockford, c))
+	}
+	return time.UnixMilli(int64(ms)).UTC(), nil
+}
+
+// encodeULID writes 128 bits as 26 base32 characters, the first of which
+// holds only the top 3 bits
+func encodeULID(data [16]byte) string {
+	hi := binary.BigEndian.Uint64(data[0:8])
+	lo := binary.BigEndian.Uint64(data[8:16])
+
+	var out [ulidLength]byte
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// increment adds one to a big-endian number and reports false on overflow
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isULID reports whether id is written in Crockford's base32 and fits in 128 bits
+func isULID(id string) bool {
+	id = strings.ToUpper(id)
+	if id == "" || id[0] > '7' {
+		return false
+	}
+	for _, c := range id {
+		if !strings.ContainsRune(crockford, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package id
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// gregorianOffset is the number of 100ns intervals between the Gregorian
+// epoch of v1 and v6 UUIDs, 1582-10-15, and the Unix epoch
+const gregorianOffset = 122192928000000000
+
+// UUIDNamespaces are the well-known namespaces of RFC 9562
+var UUIDNamespaces = map[string]uuid.UUID{
+	"dns":  uuid.NameSpaceDNS,
+	"url":  uuid.NameSpaceURL,
+	"oid":  uuid.NameSpaceOID,
+	"x500": uuid.NameSpaceX500,
+}
+
+type uuidGenerator struct {
+	version   int
+	namespace uuid.UUID
+	name      string
+}
+
+func newUUIDGenerator(opts Options) (Generator, error) {
+	g := &uuidGenerator{version: opts.Version, namespace: opts.Namespace, name: opts.Name}
+	if g.version == 0 {
+		g.version = 4
+	}
+	if g.version == 3 || g.version == 5 {
+		if g.name == "" {
+			return nil, fmt.Errorf("version %d UUIDs require a name", g.version)
+		}
+		if g.namespace == uuid.Nil {
+			g.namespace = uuid.NameSpaceDNS
+		}
+	}
+	// Fail early on unsupported versions rather than on the first ID
+	if _, err := NewUUID(g.version, g.namespace, g.name); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *uuidGenerator) New() (string, error) {
+	u, err := NewUUID(g.version, g.namespace, g.name)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (g *uuidGenerator) Time(id string) (time.Time, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UUID %q: %v", id, err)
+	}
+	t, ok := UUIDTime(u)
+	if !ok {
+		return time.Time{}, fmt.Errorf("version %d UUIDs do not contain a timestamp", u.Version())
+	}
+	return t, nil
+}
+
+// NewUUID generates a UUID of the given version. The namespace and name are
+// only used by the name-based versions 3 and 5.
+func NewUUID(version int, namespace uuid.UUID, name string) (uuid.UUID, error) {
+	switch version {
+	case 1:
+		return uuid.NewUUID()
+	case 3:
+		return uuid.NewMD5(namespace, []byte(name)), nil
+	case 4:
+		return uuid.NewRandom()
+	case 5:
+		return uuid.NewSHA1(namespace, []byte(name)), nil
+	case 6:
+		return NewV6()
+	case 7:
+		return uuid.NewV7()
+	}
+	return uuid.Nil, fmt.Errorf("unsupported UUID version %d (expected 1, 3, 4, 5, 6 or 7)", version)
+}
+
+// NewV6 generates a v6 UUID by reordering the timestamp of a v1 UUID so that
+// its most significant bits come first, as RFC 9562 specifies. uuid.NewV6
+// writes the timestamp unshifted, which the version bits then overwrite.
+func NewV6() (uuid.UUID, error) {
+	v1, err := uuid.NewUUID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	ticks := v1Ticks(v1)
+
+	var u uuid.UUID
+	binary.BigEndian.PutUint32(u[0:4], uint32(ticks>>28))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ticks>>12))
+	binary.BigEndian.PutUint16(u[6:8], 0x6000|uint16(ticks&0xfff))
+	copy(u[8:], v1[8:])
+	return u, nil
+}
+
+// ParseNamespace accepts a well-known namespace name or a UUID
+func ParseNamespace(namespace string) (uuid.UUID, error) {
+	if ns, ok := UUIDNamespaces[strings.ToLower(namespace)]; ok {
+		return ns, nil
+	}
+	ns, err := uuid.Parse(namespace)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid namespace %q: expected dns, url, oid, x500 or a UUID", namespace)
+	}
+	return ns, nil
+}
+
+// UUIDTime returns the timestamp embedded in v1, v6 and v7 UUIDs
+func UUIDTime(u uuid.UUID) (time.Time, bool) {
+	var ticks int64 // 100ns intervals since the Gregorian epoch
+	switch u.Version() {
+	case 1:
+		ticks = v1Ticks(u)
+	case 6:
+		ticks = int64(binary.BigEndian.Uint32(u[0:4]))<<28 |
+			int64(binary.BigEndian.Uint16(u[4:6]))<<12 |
+			int64(binary.BigEndian.Uint16(u[6:8])&0xfff)
+	case 7:
+		millis := int64(binary.BigEndian.Uint64(u[0:8]) >> 16)
+		return time.UnixMilli(millis).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+	return time.Unix(0, (ticks-gregorianOffset)*100).UTC(), true
+}
+
+// v1Ticks returns the timestamp of a v1 UUID, whose fields are stored low first
+func v1Ticks(u uuid.UUID) int64 {
+	return int64(binary.BigEndian.Uint32(u[0:4])) |
+		int64(binary.BigEndian.Uint16(u[4:6]))<<32 |
+		int64(binary.BigEndian.Uint16(u[6:8])&0xfff)<<48
+}
+
+func isUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
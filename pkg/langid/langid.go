@@ -0,0 +1,246 @@
+// Package langid identifies the language of a text offline.
+//
+// Each supported language has a profile of the character n-grams (one to
+// three letters, with word boundaries) of a bundled sample text. A text is
+// classified with naive Bayes over its own n-grams: the language whose
+// profile makes them most likely wins. The likelihoods are turned into
+// confidence scores that sum to 1. Since the scores only compare the
+// supported languages, a text whose three-letter n-grams are mostly missing
+// from the winner's sample is taken to be in some other language.
+package langid
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed samples/*.txt
+var samples embed.FS
+
+const (
+	// maxN is the longest n-gram in the profiles
+	maxN = 3
+	// maxEvidence caps the number of n-grams that count as evidence, so
+	// that long texts do not become certain of any language at all
+	maxEvidence = 20
+	// minLetters and minConfidence decide whether a result is reliable
+	minLetters    = 20
+	minConfidence = 0.7
+	// maxUnseen is the largest share of a text's three-letter n-grams that
+	// may be missing from the best language's sample. Texts in supported
+	// languages stay well below it, while related languages such as Estonian
+	// for Finnish go above.
+	maxUnseen = 0.4
+)
+
+// Score is the confidence that a text is in a language
+type Score struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Result is the outcome of Detect
+type Result struct {
+	// Language is the most likely language, or empty if the text has no
+	// letters or does not look like any supported language
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+	// Reliable reports whether the text is long enough and the best
+	// language far enough ahead of the others to trust the result
+	Reliable bool `json:"reliable"`
+	// Scores are the confidences of all languages, best first
+	Scores []Score `json:"scores"`
+}
+
+// Mismatch reports whether a reliable result contradicts a declared
+// language code such as "en" or "pt-BR". Regions are ignored. Declared
+// languages that are not supported never mismatch, since the text cannot be
+// told apart from them.
+func (r Result) Mismatch(declared string) bool {
+	declared = baseLanguage(declared)
+	return r.Reliable && Supported(declared) && declared != r.Language
+}
+
+// profile holds the log-probabilities of the n-grams of a language
+type profile struct {
+	lang string
+	logs map[string]float64
+	// unseen is the log-probability of an n-gram missing from the sample
+	unseen float64
+}
+
+var (
+	profilesOnce sync.Once
+	profiles     []profile
+)
+
+// Languages returns the codes of the supported languages
+func Languages() []string {
+	langs := make([]string, len(loadProfiles()))
+	for i, p := range loadProfiles() {
+		langs[i] = p.lang
+	}
+	return langs
+}
+
+// Supported reports whether a language code such as "fi" or "pt-BR" is one
+// of the supported languages
+func Supported(lang string) bool {
+	lang = baseLanguage(lang)
+	for _, p := range loadProfiles() {
+		if p.lang == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect identifies the language of a text
+func Detect(text string) Result {
+	grams, letters := ngrams(text)
+	if len(grams) == 0 {
+		return Result{}
+	}
+
+	profiles := loadProfiles()
+	logLikelihoods := make([]float64, len(profiles))
+	for i, p := range profiles {
+		for _, g := range grams {
+			if l, ok := p.logs[g]; ok {
+				logLikelihoods[i] += l
+			} else {
+				logLikelihoods[i] += p.unseen
+			}
+		}
+		// Scale to the capped amount of evidence
+		logLikelihoods[i] *= math.Min(float64(len(grams)), maxEvidence) / float64(len(grams))
+	}
+
+	// Softmax, shifted by the maximum to stay within floating point range
+	best := logLikelihoods[0]
+	for _, l := range logLikelihoods {
+		best = math.Max(best, l)
+	}
+	scores := make([]Score, len(profiles))
+	var total float64
+	for i, l := range logLikelihoods {
+		scores[i] = Score{Language: profiles[i].lang, Confidence: math.Exp(l - best)}
+		total += scores[i].Confidence
+	}
+	for i := range scores {
+		scores[i].Confidence /= total
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Confidence > scores[j].Confidence })
+
+	if unseenShare(grams, profiles, scores[0].Language) > maxUnseen {
+		return Result{Scores: scores}
+	}
+	return Result{
+		Language:   scores[0].Language,
+		Confidence: scores[0].Confidence,
+		Reliable:   letters >= minLetters && scores[0].Confidence >= minConfidence,
+		Scores:     scores,
+	}
+}
+
+// unseenShare returns the share of the three-letter n-grams missing from
+// the sample of a language
+func unseenShare(grams []string, profiles []profile, lang string) float64 {
+	var trigrams, unseen int
+	for _, p := range profiles {
+		if p.lang != lang {
+			continue
+		}
+		for _, g := range grams {
+			if utf8.RuneCountInString(g) != maxN {
+				continue
+			}
+			trigrams++
+			if _, ok := p.logs[g]; !ok {
+				unseen++
+			}
+		}
+	}
+	if trigrams == 0 {
+		return 0
+	}
+	return float64(unseen) / float64(trigrams)
+}
+
+// loadProfiles builds the profiles from the bundled samples once
+func loadProfiles() []profile {
+	profilesOnce.Do(func() {
+		entries, err := samples.ReadDir("samples")
+		if err != nil {
+			panic(err)
+		}
+
+		counts := make([]map[string]int, len(entries))
+		totals := make([]int, len(entries))
+		vocabulary := make(map[string]bool)
+		for i, e := range entries {
+			data, err := samples.ReadFile(path.Join("samples", e.Name()))
+			if err != nil {
+				panic(err)
+			}
+			counts[i] = make(map[string]int)
+			grams, _ := ngrams(string(data))
+			for _, g := range grams {
+				counts[i][g]++
+				vocabulary[g] = true
+			}
+			totals[i] = len(grams)
+		}
+
+		// Add-one smoothing over the n-grams of all samples
+		for i, e := range entries {
+			denominator := float64(totals[i] + len(vocabulary))
+			p := profile{
+				lang:   strings.TrimSuffix(e.Name(), ".txt"),
+				logs:   make(map[string]float64, len(counts[i])),
+				unseen: math.Log(1 / denominator),
+			}
+			for g, n := range counts[i] {
+				p.logs[g] = math.Log(float64(n+1) / denominator)
+			}
+			profiles = append(profiles, p)
+		}
+	})
+	return profiles
+}
+
+// ngrams returns the character n-grams of the words of a text, each word
+// padded with spaces so that its first and last letters are marked, and
+// the number of letters in the text
+func ngrams(text string) ([]string, int) {
+	var grams []string
+	letters := 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(" " + word + " ")
+		letters += len(runes) - 2
+		for n := 1; n <= maxN; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if n == 1 && runes[i] == ' ' {
+					continue
+				}
+				grams = append(grams, string(runes[i:i+n]))
+			}
+		}
+	}
+	return grams, letters
+}
+
+// baseLanguage strips the region from a language code, e.g. "en-US" -> "en"
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
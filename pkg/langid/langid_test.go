@@ -0,0 +1,110 @@
+package langid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello and welcome to this video about learning languages", "en"},
+		{"Sauna on huone tai rakennus, jossa kylvetään löylyssä.", "fi"},
+		{"Det var en gång en liten flicka som bodde i skogen", "sv"},
+		{"Ich habe heute keine Zeit, weil ich arbeiten muss", "de"},
+		{"Je ne sais pas ce que tu veux dire par là", "fr"},
+		{"No sé lo que quieres decir con eso, amigo", "es"},
+		{"Non so cosa vuoi dire con questo, amico mio", "it"},
+		{"Eu não sei o que você quer dizer com isso", "pt"},
+		{"Ik weet niet wat je daarmee bedoelt, vriend", "nl"},
+	}
+
+	for _, tt := range tests {
+		result := Detect(tt.text)
+		if result.Language != tt.want {
+			t.Errorf("Detect(%q) = %s, want %s (%v)", tt.text, result.Language, tt.want, result.Scores)
+		}
+		if !result.Reliable {
+			t.Errorf("Detect(%q) should be reliable, confidence %.2f", tt.text, result.Confidence)
+		}
+
+		var total float64
+		for i, s := range result.Scores {
+			total += s.Confidence
+			if i > 0 && s.Confidence > result.Scores[i-1].Confidence {
+				t.Errorf("Detect(%q) scores are not sorted: %v", tt.text, result.Scores)
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("Detect(%q) scores sum to %f, want 1", tt.text, total)
+		}
+	}
+}
+
+func TestDetectUnreliable(t *testing.T) {
+	for _, text := range []string{"Hej hej.", "これは日本語の文章です。テストのために書きました。"} {
+		if result := Detect(text); result.Reliable {
+			t.Errorf("Detect(%q) = %s %.2f, should not be reliable", text, result.Language, result.Confidence)
+		}
+	}
+
+	result := Detect("123 ... !")
+	if result.Language != "" || result.Reliable || len(result.Scores) != 0 {
+		t.Errorf("Detect without letters = %+v, want an empty result", result)
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	result := Detect("Saunominen on tärkeä osa suomalaista kulttuuria.")
+	if !result.Mismatch("en") {
+		t.Error("Finnish text declared as en should be a mismatch")
+	}
+	if result.Mismatch("fi-FI") || result.Mismatch("") {
+		t.Error("Finnish text declared as fi-FI or undeclared should not be a mismatch")
+	}
+	if Detect("Hej hej.").Mismatch("fi") {
+		t.Error("an unreliable result should never be a mismatch")
+	}
+}
+
+func TestDetectUnsupported(t *testing.T) {
+	for _, text := range []string{
+		"Tere kõigile ja tere tulemast minu kanalile täna räägime toidust",
+		"Eesti keel on läänemeresoome keelte hulka kuuluv keel, mida räägitakse peamiselt Eestis.",
+		"Dzień dobry, dzisiaj będziemy rozmawiać o jedzeniu i gotowaniu w domu",
+	} {
+		result := Detect(text)
+		if result.Language != "" || result.Reliable || len(result.Scores) == 0 {
+			t.Errorf("Detect(%q) = %s %.2f, want an unknown language with scores", text, result.Language, result.Confidence)
+		}
+		if result.Mismatch("fi") || result.Mismatch("et") {
+			t.Errorf("Detect(%q) should not be a mismatch", text)
+		}
+	}
+
+	// Nor is Finnish text declared as Estonian, a language without a profile
+	if Detect("Saunominen on tärkeä osa suomalaista kulttuuria.").Mismatch("et") {
+		t.Error("a declared language that is not supported should never be a mismatch")
+	}
+	if !Supported("pt-BR") || Supported("et") || Supported("") {
+		t.Error("Supported() should accept pt-BR and reject et and the empty code")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	if len(langs) != 9 {
+		t.Fatalf("Languages() = %v, want 9 languages", langs)
+	}
+	for _, want := range []string{"de", "en", "es", "fi", "fr", "it", "nl", "pt", "sv"} {
+		found := false
+		for _, l := range langs {
+			found = found || l == want
+		}
+		if !found {
+			t.Errorf("Languages() = %v, missing %s", langs, want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package stock
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Alert conditions
+const (
+	ConditionAbove     = "above"
+	ConditionBelow     = "below"
+	ConditionPctChange = "pct-change"
+)
+
+// AlertRule describes when to be alerted about a symbol. Zero thresholds are unset.
+type AlertRule struct {
+	ID     int     `yaml:"id" json:"id"`
+	Symbol string  `yaml:"symbol" json:"symbol"`
+	Above  float64 `yaml:"above,omitempty" json:"above,omitempty"`
+	Below  float64 `yaml:"below,omitempty" json:"below,omitempty"`
+	// PctChange fires when the day's change reaches this many percent either way
+	PctChange float64 `yaml:"pct_change,omitempty" json:"pctChange,omitempty"`
+}
+
+// String describes the rule's conditions, e.g. "above 250, below 200, ±5% change"
+func (r AlertRule) String() string {
+	var conditions []string
+	if r.Above != 0 {
+		conditions = append(conditions, "above "+formatFloat(r.Above))
+	}
+	if r.Below != 0 {
+		conditions = append(conditions, "below "+formatFloat(r.Below))
+	}
+	if r.PctChange != 0 {
+		conditions = append(conditions, "±"+formatFloat(r.PctChange)+"% change")
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// AlertRules is the list of alert rules saved by the user
+type AlertRules []AlertRule
+
+// LoadAlertRules reads alert rules from a YAML file holding a list of rules
+// such as
+//
+//	[{id: 1, symbol: AAPL, above: 250, below: 200}, {id: 2, symbol: NVDA, pct_change: 5}]
+//
+// A missing file yields no rules.
+func LoadAlertRules(path string) (AlertRules, error) {
+	var rules AlertRules
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading alert rules: %v", err)
+	}
+
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing alert rules file %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// Save writes the alert rules to a YAML file
+func (rules AlertRules) Save(path string) error {
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("error encoding alert rules: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating alert rules directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing alert rules: %v", err)
+	}
+	return nil
+}
+
+// Add validates a rule, assigns it the next free ID and appends it
+func (rules *AlertRules) Add(rule AlertRule) (AlertRule, error) {
+	rule.Symbol = strings.ToUpper(strings.TrimSpace(rule.Symbol))
+	if rule.Symbol == "" {
+		return AlertRule{}, fmt.Errorf("empty stock symbol")
+	}
+	if rule.Above == 0 && rule.Below == 0 && rule.PctChange == 0 {
+		return AlertRule{}, fmt.Errorf("alert rule for %s has no conditions", rule.Symbol)
+	}
+	if rule.Above < 0 || rule.Below < 0 || rule.PctChange < 0 {
+		return AlertRule{}, fmt.Errorf("alert thresholds must be positive")
+	}
+
+	for _, r := range *rules {
+		rule.ID = max(rule.ID, r.ID)
+	}
+	rule.ID++
+	*rules = append(*rules, rule)
+	return rule, nil
+}
+
+// Remove deletes the rule with the given ID
+func (rules *AlertRules) Remove(id int) error {
+	for i, r := range *rules {
+		if r.ID == id {
+			*rules = append((*rules)[:i], (*rules)[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no alert rule with ID %d", id)
+}
+
+// Symbols returns the distinct symbols the rules watch, in rule order
+func (rules AlertRules) Symbols() []string {
+	var symbols []string
+	for _, r := range rules {
+		symbols = append(symbols, r.Symbol)
+	}
+	return NormalizeSymbols(symbols)
+}
+
+// Alert is a rule condition met by a quote
+type Alert struct {
+	Rule      AlertRule `json:"rule"`
+	Condition string    `json:"condition"`
+	Threshold float64   `json:"threshold"`
+	Quote     *Quote    `json:"quote"`
+}
+
+// Message describes the alert, e.g. "AAPL is above 250: 254.49 +1.88 (+0.74%)"
+func (a Alert) Message() string {
+	q := a.Quote
+	var condition string
+	switch a.Condition {
+	case ConditionPctChange:
+		condition = "moved ±" + formatFloat(a.Threshold) + "%"
+	default:
+		condition = "is " + a.Condition + " " + formatFloat(a.Threshold)
+	}
+	return fmt.Sprintf("%s %s: %.2f %s", q.Symbol, condition, q.Price, FormatChange(q.Change, q.ChangePercent))
+}
+
+// AlertMonitor checks quotes against rules. An alert fires once when its
+// condition starts to hold and fires again only after the condition stopped
+// holding in between, so a price hovering above a threshold alerts once per crossing.
+type AlertMonitor struct {
+	active map[string]bool
+}
+
+// NewAlertMonitor creates an AlertMonitor with no conditions active
+func NewAlertMonitor() *AlertMonitor {
+	return &AlertMonitor{active: make(map[string]bool)}
+}
+
+// alertState is the content of an alert state file
+type alertState struct {
+	// Active are the conditions that held when last checked
+	Active []string `yaml:"active"`
+}
+
+// LoadAlertMonitor creates an AlertMonitor with the conditions saved by
+// Save active, so that alerts fired by an earlier run, e.g. a previous
+// "alert run --once" from cron, do not fire again. A missing file yields no
+// active conditions.
+func LoadAlertMonitor(path string) (*AlertMonitor, error) {
+	m := NewAlertMonitor()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading alert state: %v", err)
+	}
+
+	var state alertState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error parsing alert state file %s: %v", path, err)
+	}
+	for _, key := range state.Active {
+		m.active[key] = true
+	}
+	return m, nil
+}
+
+// Save writes the active conditions of the rules to a YAML file. Conditions
+// of rules no longer in rules are dropped.
+func (m *AlertMonitor) Save(path string, rules AlertRules) error {
+	var state alertState
+	for _, rule := range rules {
+		for _, c := range rule.conditions() {
+			if key := alertKey(rule, c.name, c.threshold); m.active[key] {
+				state.Active = append(state.Active, key)
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error encoding alert state: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating alert state directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing alert state: %v", err)
+	}
+	return nil
+}
+
+// alertCondition is a condition of a rule with its threshold
+type alertCondition struct {
+	name      string
+	threshold float64
+}
+
+// conditions returns the conditions set on the rule
+func (r AlertRule) conditions() []alertCondition {
+	var conditions []alertCondition
+	for _, c := range []alertCondition{{ConditionAbove, r.Above}, {ConditionBelow, r.Below}, {ConditionPctChange, r.PctChange}} {
+		if c.threshold != 0 {
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions
+}
+
+// alertKey identifies a condition of a rule. It includes the symbol and
+// threshold so that a rule removed and added again with the same ID but
+// other settings starts inactive.
+func alertKey(rule AlertRule, condition string, threshold float64) string {
+	return fmt.Sprintf("%d/%s/%s/%s", rule.ID, rule.Symbol, condition, formatFloat(threshold))
+}
+
+// Check returns the alerts newly triggered by a quote
+func (m *AlertMonitor) Check(rules AlertRules, q *Quote) []Alert {
+	var alerts []Alert
+	for _, rule := range rules {
+		if rule.Symbol != q.Symbol {
+			continue
+		}
+
+		for _, c := range rule.conditions() {
+			var met bool
+			switch c.name {
+			case ConditionAbove:
+				met = q.Price > c.threshold
+			case ConditionBelow:
+				met = q.Price < c.threshold
+			case ConditionPctChange:
+				met = math.Abs(q.ChangePercent) >= c.threshold
+			}
+			key := alertKey(rule, c.name, c.threshold)
+			if met && !m.active[key] {
+				alerts = append(alerts, Alert{Rule: rule, Condition: c.name, Threshold: c.threshold, Quote: q})
+			}
+			m.active[key] = met
+		}
+	}
+	return alerts
+}
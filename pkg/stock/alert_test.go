@@ -0,0 +1,115 @@
+package stock
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAlertMonitorFiresOncePerCrossing(t *testing.T) {
+	rules := AlertRules{{ID: 1, Symbol: "AAPL", Above: 250, Below: 200}}
+	m := NewAlertMonitor()
+
+	prices := []float64{240, 251, 255, 249, 252, 199, 198}
+	want := []string{"", ConditionAbove, "", "", ConditionAbove, ConditionBelow, ""}
+
+	for i, price := range prices {
+		alerts := m.Check(rules, &Quote{Symbol: "AAPL", Price: price})
+		got := ""
+		if len(alerts) > 1 {
+			t.Fatalf("price %v: expected at most one alert, got %d", price, len(alerts))
+		}
+		if len(alerts) == 1 {
+			got = alerts[0].Condition
+		}
+		if got != want[i] {
+			t.Errorf("price %v: alert %q, want %q", price, got, want[i])
+		}
+	}
+}
+
+func TestAlertMonitorState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts-state.yaml")
+	rules := AlertRules{{ID: 1, Symbol: "AAPL", Above: 250, Below: 200}, {ID: 2, Symbol: "NOK", PctChange: 3}}
+
+	m, err := LoadAlertMonitor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts := m.Check(rules, &Quote{Symbol: "AAPL", Price: 251}); len(alerts) != 1 {
+		t.Fatalf("expected the above alert to fire, got %v", alerts)
+	}
+	m.Check(rules, &Quote{Symbol: "NOK", Price: 4, ChangePercent: 4})
+	// Rule 2 was removed, so its condition is not kept
+	if err := m.Save(path, rules[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadAlertMonitor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts := m.Check(rules, &Quote{Symbol: "AAPL", Price: 252}); len(alerts) != 0 {
+		t.Errorf("expected the saved state to keep the alert from firing again, got %v", alerts)
+	}
+	if alerts := m.Check(rules, &Quote{Symbol: "NOK", Price: 4, ChangePercent: 4}); len(alerts) != 1 {
+		t.Errorf("expected the removed rule's state to be dropped, got %v", alerts)
+	}
+
+	// A rule with the same ID but another threshold starts inactive
+	changed := AlertRules{{ID: 1, Symbol: "AAPL", Above: 240}}
+	if alerts := m.Check(changed, &Quote{Symbol: "AAPL", Price: 252}); len(alerts) != 1 {
+		t.Errorf("expected a changed rule to fire, got %v", alerts)
+	}
+}
+
+func TestAlertMonitorPctChange(t *testing.T) {
+	rules := AlertRules{{ID: 1, Symbol: "NVDA", PctChange: 5}}
+	m := NewAlertMonitor()
+
+	alerts := m.Check(rules, &Quote{Symbol: "NVDA", Price: 130, Change: -7.2, ChangePercent: -5.25})
+	if len(alerts) != 1 {
+		t.Fatalf("expected an alert for a -5.25%% move, got %d", len(alerts))
+	}
+	if msg := alerts[0].Message(); msg != "NVDA moved ±5%: 130.00 -7.20 (-5.25%)" {
+		t.Errorf("message = %q", msg)
+	}
+
+	if alerts := m.Check(rules, &Quote{Symbol: "MSFT", Price: 1, ChangePercent: 9}); len(alerts) != 0 {
+		t.Errorf("rules for other symbols should not fire, got %v", alerts)
+	}
+}
+
+func TestAlertRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+
+	var rules AlertRules
+	if _, err := rules.Add(AlertRule{Symbol: "aapl"}); err == nil {
+		t.Error("expected a rule without conditions to be rejected")
+	}
+	first, _ := rules.Add(AlertRule{Symbol: "aapl", Above: 250})
+	second, _ := rules.Add(AlertRule{Symbol: "NOK", Below: 4, PctChange: 3})
+	if first.ID != 1 || second.ID != 2 || first.Symbol != "AAPL" {
+		t.Errorf("unexpected rules: %+v %+v", first, second)
+	}
+	if s := second.String(); s != "below 4, ±3% change" {
+		t.Errorf("String() = %q", s)
+	}
+
+	if err := rules.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := rules.Remove(1); err == nil {
+		t.Error("expected removing a missing rule to fail")
+	}
+	if err := rules.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadAlertRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || loaded[0] != second {
+		t.Errorf("loaded %+v, want %+v", loaded, second)
+	}
+}
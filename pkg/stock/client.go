@@ -0,0 +1,119 @@
+package stock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultBaseURL is the Yahoo Finance site quotes are read from
+const DefaultBaseURL = "https://finance.yahoo.com"
+
+// Client fetches stock data from Yahoo Finance
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	chartURL   string
+}
+
+// ClientOption defines a function to configure the Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the Yahoo Finance site URL, mainly for testing
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithChartURL sets the chart endpoint used for price history, mainly for testing
+func WithChartURL(chartURL string) ClientOption {
+	return func(c *Client) {
+		c.chartURL = strings.TrimSuffix(chartURL, "/")
+	}
+}
+
+// NewClient creates a new Yahoo Finance client
+func NewClient(options ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{},
+		baseURL:    DefaultBaseURL,
+		chartURL:   DefaultChartURL,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// GetQuote fetches the current quote for a symbol
+func (c *Client) GetQuote(symbol string) (*Quote, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("empty stock symbol")
+	}
+
+	quoteURL := fmt.Sprintf("%s/quote/%s", c.baseURL, url.PathEscape(symbol))
+	req, err := http.NewRequest("GET", quoteURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrSymbolNotFound{Symbol: symbol}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching quote for %s", resp.StatusCode, symbol)
+	}
+
+	return ParseQuote(resp.Body, symbol)
+}
+
+// QuoteResult is the outcome of fetching one symbol with GetQuotes
+type QuoteResult struct {
+	Symbol string
+	Quote  *Quote
+	Err    error
+}
+
+// GetQuotes fetches quotes for several symbols with at most concurrency requests
+// in flight. Results are returned in the order of symbols.
+func (c *Client) GetQuotes(symbols []string, concurrency int) []QuoteResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]QuoteResult, len(symbols))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			quote, err := c.GetQuote(symbol)
+			results[i] = QuoteResult{Symbol: symbol, Quote: quote, Err: err}
+		}(i, symbol)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,65 @@
+package stock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVHeader returns the column names matching Quote.CSVRecord
+func CSVHeader() []string {
+	return []string{
+		"symbol", "name", "exchange", "currency", "price", "change", "change_percent", "market_state",
+		"pre_market_price", "post_market_price", "timestamp",
+	}
+}
+
+// CSVRecord returns the quote as a CSV row in the order of CSVHeader
+func (q Quote) CSVRecord() []string {
+	timestamp := ""
+	if !q.Timestamp.IsZero() {
+		timestamp = q.Timestamp.Format(time.RFC3339)
+	}
+	return []string{
+		q.Symbol, q.Name, q.Exchange, q.Currency,
+		formatFloat(q.Price), formatFloat(q.Change), formatFloat(q.ChangePercent), q.MarketState,
+		optionalFloat(q.PreMarketPrice), optionalFloat(q.PostMarketPrice), timestamp,
+	}
+}
+
+// String renders the quote for terminal output
+func (q Quote) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", q.Name, q.Symbol)
+	if q.Exchange != "" || q.Currency != "" {
+		fmt.Fprintf(&b, "%s\n", strings.Trim(q.Exchange+" · "+q.Currency, " ·"))
+	}
+	fmt.Fprintf(&b, "%.2f %s\n", q.Price, FormatChange(q.Change, q.ChangePercent))
+	if q.MarketTime != "" {
+		fmt.Fprintf(&b, "%s\n", q.MarketTime)
+	}
+	if q.PreMarketPrice != 0 {
+		fmt.Fprintf(&b, "Pre-market: %.2f %s\n", q.PreMarketPrice, FormatChange(q.PreMarketChange, q.PreMarketChangePercent))
+	}
+	if q.PostMarketPrice != 0 {
+		fmt.Fprintf(&b, "After hours: %.2f %s\n", q.PostMarketPrice, FormatChange(q.PostMarketChange, q.PostMarketChangePercent))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatChange renders a price change as shown by Yahoo Finance, e.g. "+1.88 (+0.74%)"
+func FormatChange(change, percent float64) string {
+	return fmt.Sprintf("%+.2f (%+.2f%%)", change, percent)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func optionalFloat(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return formatFloat(f)
+}
@@ -0,0 +1,278 @@
+package stock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultChartURL is Yahoo Finance's chart endpoint used for price history
+const DefaultChartURL = "https://query1.finance.yahoo.com/v8/finance/chart"
+
+// Intervals are the supported history intervals
+var Intervals = []string{"1d", "1wk", "1mo"}
+
+// Bar is the price data for one interval
+type Bar struct {
+	Time     time.Time `json:"time"`
+	Open     float64   `json:"open"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Close    float64   `json:"close"`
+	AdjClose float64   `json:"adjClose"`
+	Volume   int64     `json:"volume"`
+}
+
+// Dividend is a dividend payment
+type Dividend struct {
+	Date   time.Time `json:"date"`
+	Amount float64   `json:"amount"`
+}
+
+// Split is a stock split, e.g. 4:1 has Numerator 4 and Denominator 1
+type Split struct {
+	Date        time.Time `json:"date"`
+	Numerator   float64   `json:"numerator"`
+	Denominator float64   `json:"denominator"`
+	Ratio       string    `json:"ratio"`
+}
+
+// History is a symbol's price history with the dividends and splits in the same period
+type History struct {
+	Symbol    string     `json:"symbol"`
+	Currency  string     `json:"currency"`
+	Exchange  string     `json:"exchange"`
+	Interval  string     `json:"interval"`
+	Bars      []Bar      `json:"bars"`
+	Dividends []Dividend `json:"dividends"`
+	Splits    []Split    `json:"splits"`
+}
+
+// chartResponse mirrors the parts of the chart endpoint response we use.
+// Values are pointers because Yahoo reports missing data points as null.
+type chartResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Currency        string `json:"currency"`
+				Symbol          string `json:"symbol"`
+				ExchangeName    string `json:"exchangeName"`
+				GMTOffset       int    `json:"gmtoffset"`
+				Timezone        string `json:"timezone"`
+				DataGranularity string `json:"dataGranularity"`
+			} `json:"meta"`
+			Timestamp []int64 `json:"timestamp"`
+			Events    struct {
+				Dividends map[string]struct {
+					Amount float64 `json:"amount"`
+					Date   int64   `json:"date"`
+				} `json:"dividends"`
+				Splits map[string]struct {
+					Date        int64   `json:"date"`
+					Numerator   float64 `json:"numerator"`
+					Denominator float64 `json:"denominator"`
+					SplitRatio  string  `json:"splitRatio"`
+				} `json:"splits"`
+			} `json:"events"`
+			Indicators struct {
+				Quote []struct {
+					Open   []*float64 `json:"open"`
+					High   []*float64 `json:"high"`
+					Low    []*float64 `json:"low"`
+					Close  []*float64 `json:"close"`
+					Volume []*int64   `json:"volume"`
+				} `json:"quote"`
+				AdjClose []struct {
+					AdjClose []*float64 `json:"adjclose"`
+				} `json:"adjclose"`
+			} `json:"indicators"`
+		} `json:"result"`
+		Error *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// ValidInterval reports whether interval is one of Intervals
+func ValidInterval(interval string) bool {
+	for _, i := range Intervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHistory fetches the price history of a symbol between from and to, inclusive
+func (c *Client) GetHistory(symbol string, from, to time.Time, interval string) (*History, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("empty stock symbol")
+	}
+	if !ValidInterval(interval) {
+		return nil, fmt.Errorf("invalid interval %q (expected one of %s)", interval, strings.Join(Intervals, ", "))
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date %s is before start date %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	query := url.Values{}
+	query.Set("period1", strconv.FormatInt(from.Unix(), 10))
+	query.Set("period2", strconv.FormatInt(to.Unix(), 10))
+	query.Set("interval", interval)
+	query.Set("events", "div|split")
+	query.Set("includeAdjustedClose", "true")
+	chartURL := fmt.Sprintf("%s/%s?%s", c.chartURL, url.PathEscape(symbol), query.Encode())
+
+	req, err := http.NewRequest("GET", chartURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching history: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Errors come back as JSON too, so the body is parsed regardless of status
+	history, err := ParseHistory(resp.Body)
+	if err != nil {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrSymbolNotFound{Symbol: symbol}
+		}
+		return nil, err
+	}
+	history.Interval = interval
+	return history, nil
+}
+
+// ParseHistory parses a response from Yahoo Finance's chart endpoint
+func ParseHistory(r io.Reader) (*History, error) {
+	var resp chartResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("error parsing chart response: %v", err)
+	}
+
+	if e := resp.Chart.Error; e != nil {
+		return nil, fmt.Errorf("chart error %s: %s", e.Code, e.Description)
+	}
+	if len(resp.Chart.Result) == 0 {
+		return nil, fmt.Errorf("chart response contains no results")
+	}
+
+	result := resp.Chart.Result[0]
+	loc := time.FixedZone(result.Meta.Timezone, result.Meta.GMTOffset)
+	h := &History{
+		Symbol:    result.Meta.Symbol,
+		Currency:  result.Meta.Currency,
+		Exchange:  result.Meta.ExchangeName,
+		Interval:  result.Meta.DataGranularity,
+		Bars:      []Bar{},
+		Dividends: []Dividend{},
+		Splits:    []Split{},
+	}
+
+	if len(result.Indicators.Quote) > 0 {
+		quote := result.Indicators.Quote[0]
+		var adjClose []*float64
+		if len(result.Indicators.AdjClose) > 0 {
+			adjClose = result.Indicators.AdjClose[0].AdjClose
+		}
+
+		for i, ts := range result.Timestamp {
+			closePrice := floatAt(quote.Close, i)
+			if closePrice == nil {
+				// No trades in this interval, e.g. a trading halt
+				continue
+			}
+			bar := Bar{
+				Time:     time.Unix(ts, 0).In(loc),
+				Close:    *closePrice,
+				AdjClose: *closePrice,
+			}
+			if v := floatAt(quote.Open, i); v != nil {
+				bar.Open = *v
+			}
+			if v := floatAt(quote.High, i); v != nil {
+				bar.High = *v
+			}
+			if v := floatAt(quote.Low, i); v != nil {
+				bar.Low = *v
+			}
+			if v := floatAt(adjClose, i); v != nil {
+				bar.AdjClose = *v
+			}
+			if i < len(quote.Volume) && quote.Volume[i] != nil {
+				bar.Volume = *quote.Volume[i]
+			}
+			h.Bars = append(h.Bars, bar)
+		}
+	}
+
+	for _, d := range result.Events.Dividends {
+		h.Dividends = append(h.Dividends, Dividend{Date: time.Unix(d.Date, 0).In(loc), Amount: d.Amount})
+	}
+	sort.Slice(h.Dividends, func(i, j int) bool { return h.Dividends[i].Date.Before(h.Dividends[j].Date) })
+
+	for _, s := range result.Events.Splits {
+		h.Splits = append(h.Splits, Split{
+			Date:        time.Unix(s.Date, 0).In(loc),
+			Numerator:   s.Numerator,
+			Denominator: s.Denominator,
+			Ratio:       s.SplitRatio,
+		})
+	}
+	sort.Slice(h.Splits, func(i, j int) bool { return h.Splits[i].Date.Before(h.Splits[j].Date) })
+
+	return h, nil
+}
+
+func floatAt(values []*float64, i int) *float64 {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// HistoryCSVHeader returns the column names matching History.CSVRecords
+func HistoryCSVHeader() []string {
+	return []string{"date", "open", "high", "low", "close", "adj_close", "volume", "dividend", "split"}
+}
+
+// CSVRecords returns one CSV row per bar. Dividends and splits are reported
+// on the row of the bar they fall on.
+func (h *History) CSVRecords() [][]string {
+	dividends := make(map[string]float64)
+	for _, d := range h.Dividends {
+		dividends[d.Date.Format("2006-01-02")] += d.Amount
+	}
+	splits := make(map[string]string)
+	for _, s := range h.Splits {
+		splits[s.Date.Format("2006-01-02")] = s.Ratio
+	}
+
+	var records [][]string
+	for _, bar := range h.Bars {
+		date := bar.Time.Format("2006-01-02")
+		dividend := ""
+		if amount, ok := dividends[date]; ok {
+			dividend = formatFloat(amount)
+		}
+		records = append(records, []string{
+			date,
+			formatFloat(bar.Open), formatFloat(bar.High), formatFloat(bar.Low),
+			formatFloat(bar.Close), formatFloat(bar.AdjClose),
+			strconv.FormatInt(bar.Volume, 10),
+			dividend, splits[date],
+		})
+	}
+	return records
+}
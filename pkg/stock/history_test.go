@@ -0,0 +1,84 @@
+package stock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHistory(t *testing.T) {
+	f, err := os.Open("testdata/chart_nvda_1d.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	h, err := ParseHistory(f)
+	if err != nil {
+		t.Fatalf("ParseHistory failed: %v", err)
+	}
+
+	if h.Symbol != "NVDA" || h.Currency != "USD" || h.Interval != "1d" {
+		t.Errorf("unexpected metadata: %+v", h)
+	}
+
+	// The fixture has 7 timestamps, one of which has no data
+	if len(h.Bars) != 6 {
+		t.Fatalf("got %d bars, want 6", len(h.Bars))
+	}
+	first := h.Bars[0]
+	if first.Time.Format("2006-01-02 15:04") != "2024-06-05 09:30" {
+		t.Errorf("first bar time = %v", first.Time)
+	}
+	if first.Open != 118.37 || first.High != 122.45 || first.Low != 117.47 || first.Close != 122.44 || first.AdjClose != 122.42 || first.Volume != 377450300 {
+		t.Errorf("unexpected first bar: %+v", first)
+	}
+
+	if len(h.Splits) != 1 || h.Splits[0].Ratio != "10:1" || h.Splits[0].Numerator != 10 {
+		t.Errorf("unexpected splits: %+v", h.Splits)
+	}
+	if len(h.Dividends) != 1 || h.Dividends[0].Amount != 0.01 || h.Dividends[0].Date.Format("2006-01-02") != "2024-06-11" {
+		t.Errorf("unexpected dividends: %+v", h.Dividends)
+	}
+
+	records := h.CSVRecords()
+	if got := strings.Join(records[3], ","); got != "2024-06-10,120.37,123.1,117.01,121.79,121.77,314162700,,10:1" {
+		t.Errorf("unexpected split row: %s", got)
+	}
+	if got := records[4][7]; got != "0.01" {
+		t.Errorf("unexpected dividend column: %q", got)
+	}
+}
+
+func TestGetHistoryNotFound(t *testing.T) {
+	body, err := os.ReadFile("testdata/chart_not_found.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/XYZ" || r.URL.Query().Get("interval") != "1wk" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithChartURL(server.URL))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = client.GetHistory("xyz", from, from.AddDate(0, 1, 0), "1wk")
+	if _, ok := err.(ErrSymbolNotFound); !ok {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+}
+
+func TestGetHistoryInvalidInterval(t *testing.T) {
+	client := NewClient()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := client.GetHistory("AAPL", from, from.AddDate(0, 1, 0), "1h"); err == nil {
+		t.Error("expected an error for an unsupported interval")
+	}
+}
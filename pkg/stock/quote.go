@@ -0,0 +1,221 @@
+// Package stock retrieves stock quotes from Yahoo Finance.
+package stock
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Market states reported in Quote.MarketState
+const (
+	MarketPre     = "PRE"
+	MarketRegular = "REGULAR"
+	MarketPost    = "POST"
+	MarketClosed  = "CLOSED"
+)
+
+// ErrSymbolNotFound is returned when Yahoo Finance has no quote page for a symbol
+type ErrSymbolNotFound struct {
+	Symbol string
+}
+
+func (e ErrSymbolNotFound) Error() string {
+	return fmt.Sprintf("no quote found for symbol %s", e.Symbol)
+}
+
+// Quote is a snapshot of a stock's price as shown on its quote page
+type Quote struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name"`
+	Exchange      string  `json:"exchange,omitempty"`
+	Currency      string  `json:"currency,omitempty"`
+	Price         float64 `json:"price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+	MarketState   string  `json:"marketState,omitempty"`
+
+	PreMarketPrice         float64 `json:"preMarketPrice,omitempty"`
+	PreMarketChange        float64 `json:"preMarketChange,omitempty"`
+	PreMarketChangePercent float64 `json:"preMarketChangePercent,omitempty"`
+
+	PostMarketPrice         float64 `json:"postMarketPrice,omitempty"`
+	PostMarketChange        float64 `json:"postMarketChange,omitempty"`
+	PostMarketChangePercent float64 `json:"postMarketChangePercent,omitempty"`
+
+	// Timestamp is the time of the regular market price
+	Timestamp time.Time `json:"timestamp"`
+	// MarketTime is the market time notice as displayed, e.g. "At close: December 20 at 4:00:01 PM EST"
+	MarketTime string `json:"marketTime,omitempty"`
+
+	// Stats holds the summary statistics, if the page shows them
+	Stats *Statistics `json:"stats,omitempty"`
+}
+
+// now is replaceable so that tests can pin the year of parsed timestamps
+var now = time.Now
+
+var (
+	titleRe    = regexp.MustCompile(`^(.*?)\s*\(([^()]+)\)\s*$`)
+	exchangeRe = regexp.MustCompile(`^\s*(.+?)\s+-\s+.*?•\s*([A-Z]{3})\s*$`)
+	currencyRe = regexp.MustCompile(`Currency in ([A-Z]{3})`)
+)
+
+// ParseQuote extracts a quote from a Yahoo Finance quote page
+func ParseQuote(r io.Reader, symbol string) (*Quote, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing page: %v", err)
+	}
+
+	// Get title from main h1, e.g. "Apple Inc. (AAPL)"
+	title := strings.TrimSpace(doc.Find("main h1").First().Text())
+	if title == "" {
+		return nil, ErrSymbolNotFound{Symbol: symbol}
+	}
+
+	q := &Quote{Symbol: strings.ToUpper(symbol), Name: title}
+	if m := titleRe.FindStringSubmatch(title); m != nil {
+		q.Name = m[1]
+		q.Symbol = m[2]
+	}
+
+	header := doc.Find("section[data-testid='quote-hdr']").First()
+	exchange := strings.TrimSpace(header.Find(".exchange").First().Text())
+	if m := exchangeRe.FindStringSubmatch(exchange); m != nil {
+		q.Exchange = m[1]
+		q.Currency = m[2]
+	} else if m := currencyRe.FindStringSubmatch(doc.Text()); m != nil {
+		q.Currency = m[1]
+	}
+
+	priceSection := doc.Find("section[data-testid='quote-price']").First()
+	if priceSection.Length() == 0 {
+		return nil, fmt.Errorf("could not find price section")
+	}
+
+	if q.Price, err = fieldValue(priceSection, "regularMarketPrice", "qsp-price"); err != nil {
+		return nil, err
+	}
+	q.Change, _ = fieldValue(priceSection, "regularMarketChange", "qsp-price-change")
+	q.ChangePercent, _ = fieldValue(priceSection, "regularMarketChangePercent", "qsp-price-change-percent")
+
+	q.PreMarketPrice, _ = fieldValue(priceSection, "preMarketPrice", "qsp-pre-price")
+	q.PreMarketChange, _ = fieldValue(priceSection, "preMarketChange", "qsp-pre-price-change")
+	q.PreMarketChangePercent, _ = fieldValue(priceSection, "preMarketChangePercent", "qsp-pre-price-change-percent")
+
+	q.PostMarketPrice, _ = fieldValue(priceSection, "postMarketPrice", "qsp-post-price")
+	q.PostMarketChange, _ = fieldValue(priceSection, "postMarketChange", "qsp-post-price-change")
+	q.PostMarketChangePercent, _ = fieldValue(priceSection, "postMarketChangePercent", "qsp-post-price-change-percent")
+
+	// The first notice belongs to the regular market price, a second one to extended hours
+	var notices []string
+	priceSection.Find("[slot='marketTimeNotice']").Each(func(i int, s *goquery.Selection) {
+		notices = append(notices, strings.TrimSpace(s.Text()))
+	})
+	if len(notices) > 0 {
+		q.MarketTime = notices[0]
+		q.Timestamp, _ = ParseMarketTime(notices[0], now())
+	}
+	q.MarketState = marketState(notices)
+	q.Stats = parseStatistics(doc)
+
+	return q, nil
+}
+
+// fieldValue reads a numeric value from the fin-streamer element for the given data field,
+// falling back to the element's test ID and its displayed text
+func fieldValue(s *goquery.Selection, field, testID string) (float64, error) {
+	el := s.Find(fmt.Sprintf("fin-streamer[data-field='%s']", field)).First()
+	if el.Length() == 0 {
+		el = s.Find(fmt.Sprintf("[data-testid='%s']", testID)).First()
+	}
+	if el.Length() == 0 {
+		return 0, fmt.Errorf("could not find %s", field)
+	}
+
+	if value, ok := el.Attr("data-value"); ok {
+		if f, err := ParseNumber(value); err == nil {
+			return f, nil
+		}
+	}
+	return ParseNumber(el.Text())
+}
+
+// ParseNumber parses a displayed number such as "+1.88", "(-0.74%)" or "147,495,267"
+func ParseNumber(s string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", "%", "", "(", "", ")", "", "+", "").Replace(strings.TrimSpace(s))
+	f, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
+
+func marketState(notices []string) string {
+	for _, notice := range notices {
+		lower := strings.ToLower(notice)
+		switch {
+		case strings.HasPrefix(lower, "pre-market"):
+			return MarketPre
+		case strings.HasPrefix(lower, "after hours"), strings.HasPrefix(lower, "post-market"), strings.HasPrefix(lower, "overnight"):
+			return MarketPost
+		}
+	}
+	if len(notices) == 0 {
+		return ""
+	}
+	if strings.Contains(strings.ToLower(notices[0]), "market open") {
+		return MarketRegular
+	}
+	return MarketClosed
+}
+
+var marketTimeRe = regexp.MustCompile(`([A-Z][a-z]+ \d{1,2}) at (\d{1,2}:\d{2}(?::\d{2})? [AP]M) ([A-Z]{2,5})`)
+
+// zoneOffsets are the time zones Yahoo Finance uses in market time notices
+var zoneOffsets = map[string]int{
+	"EST": -5 * 3600, "EDT": -4 * 3600,
+	"CST": -6 * 3600, "CDT": -5 * 3600,
+	"PST": -8 * 3600, "PDT": -7 * 3600,
+	"GMT": 0, "UTC": 0, "BST": 3600,
+	"CET": 3600, "CEST": 2 * 3600,
+	"EET": 2 * 3600, "EEST": 3 * 3600,
+	"JST": 9 * 3600, "HKT": 8 * 3600,
+}
+
+// ParseMarketTime parses a market time notice such as "At close: December 20 at 4:00:01 PM EST".
+// Notices omit the year, so the most recent matching date not after now is used.
+func ParseMarketTime(notice string, now time.Time) (time.Time, error) {
+	m := marketTimeRe.FindStringSubmatch(notice)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("no market time in %q", notice)
+	}
+
+	offset, ok := zoneOffsets[m[3]]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown time zone %s", m[3])
+	}
+	loc := time.FixedZone(m[3], offset)
+
+	clock := m[2]
+	layout := "January 2 3:04:05 PM"
+	if strings.Count(clock, ":") == 1 {
+		layout = "January 2 3:04 PM"
+	}
+	t, err := time.ParseInLocation(layout, m[1]+" "+clock, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t = t.AddDate(now.In(loc).Year(), 0, 0)
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, nil
+}
@@ -0,0 +1,124 @@
+package stock
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseFixture(t *testing.T, name, symbol string) *Quote {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	q, err := ParseQuote(f, symbol)
+	if err != nil {
+		t.Fatalf("ParseQuote failed: %v", err)
+	}
+	return q
+}
+
+func pinNow(t *testing.T, at time.Time) {
+	t.Helper()
+	original := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = original })
+}
+
+func TestParseQuote(t *testing.T) {
+	pinNow(t, time.Date(2024, 12, 22, 12, 0, 0, 0, time.UTC))
+	q := parseFixture(t, "quote_aapl.html", "aapl")
+
+	if q.Symbol != "AAPL" || q.Name != "Apple Inc." {
+		t.Errorf("symbol/name = %q/%q", q.Symbol, q.Name)
+	}
+	if q.Exchange != "NasdaqGS" || q.Currency != "USD" {
+		t.Errorf("exchange/currency = %q/%q", q.Exchange, q.Currency)
+	}
+	if q.Price != 254.49 {
+		t.Errorf("price = %v", q.Price)
+	}
+	if q.Change != 1.8800049 || q.ChangePercent != 0.7442597 {
+		t.Errorf("change = %v (%v%%)", q.Change, q.ChangePercent)
+	}
+	if q.PostMarketPrice != 254.7 || q.PostMarketChange != 0.2099915 {
+		t.Errorf("post-market = %v %v", q.PostMarketPrice, q.PostMarketChange)
+	}
+	if q.PreMarketPrice != 0 {
+		t.Errorf("unexpected pre-market price %v", q.PreMarketPrice)
+	}
+	if q.MarketState != MarketPost {
+		t.Errorf("market state = %q", q.MarketState)
+	}
+
+	want := time.Date(2024, 12, 20, 21, 0, 1, 0, time.UTC)
+	if !q.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", q.Timestamp, want)
+	}
+}
+
+func TestParseQuotePreMarket(t *testing.T) {
+	pinNow(t, time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC))
+	q := parseFixture(t, "quote_nokia_premarket.html", "NOK")
+
+	if q.Price != 4.47 || q.Change != -0.05 || q.ChangePercent != -1.11 {
+		t.Errorf("regular market = %v %v %v", q.Price, q.Change, q.ChangePercent)
+	}
+	if q.PreMarketPrice != 4.52 || q.PreMarketChangePercent != 1.12 {
+		t.Errorf("pre-market = %v (%v%%)", q.PreMarketPrice, q.PreMarketChangePercent)
+	}
+	if q.MarketState != MarketPre {
+		t.Errorf("market state = %q", q.MarketState)
+	}
+	if q.Timestamp.Year() != 2025 || q.Timestamp.Hour() != 16 {
+		t.Errorf("timestamp = %v", q.Timestamp)
+	}
+}
+
+func TestParseQuoteNotFound(t *testing.T) {
+	_, err := ParseQuote(strings.NewReader("<html><body><main><p>Symbols similar to XYZ</p></main></body></html>"), "xyz")
+	if _, ok := err.(ErrSymbolNotFound); !ok {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+}
+
+func TestParseMarketTime(t *testing.T) {
+	ref := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notice string
+		want   time.Time
+	}{
+		{"At close: January 2 at 4:00:01 PM EST", time.Date(2025, 1, 2, 21, 0, 1, 0, time.UTC)},
+		{"As of December 31 at 10:15 AM EST. Market open.", time.Date(2024, 12, 31, 15, 15, 0, 0, time.UTC)},
+		{"At close: July 1 at 5:35:00 PM CEST", time.Date(2024, 7, 1, 15, 35, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseMarketTime(tt.notice, ref)
+		if err != nil {
+			t.Errorf("ParseMarketTime(%q) failed: %v", tt.notice, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseMarketTime(%q) = %v, want %v", tt.notice, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := map[string]float64{
+		"+1.88":       1.88,
+		"(-0.74%)":    -0.74,
+		"147,495,267": 147495267,
+		" 254.49 ":    254.49,
+	}
+	for input, want := range tests {
+		got, err := ParseNumber(input)
+		if err != nil || got != want {
+			t.Errorf("ParseNumber(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package stock
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Range is a low-high price range
+type Range struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// Statistics are the summary statistics shown below the price on the quote page.
+// Values that are not shown or not available ("N/A", "--") are left zero.
+type Statistics struct {
+	PreviousClose     float64    `json:"previousClose,omitempty"`
+	Open              float64    `json:"open,omitempty"`
+	DayRange          *Range     `json:"dayRange,omitempty"`
+	FiftyTwoWeekRange *Range     `json:"fiftyTwoWeekRange,omitempty"`
+	Volume            int64      `json:"volume,omitempty"`
+	AvgVolume         int64      `json:"avgVolume,omitempty"`
+	MarketCap         float64    `json:"marketCap,omitempty"`
+	Beta              float64    `json:"beta,omitempty"`
+	PERatio           float64    `json:"peRatio,omitempty"`
+	EPS               float64    `json:"eps,omitempty"`
+	ForwardDividend   float64    `json:"forwardDividend,omitempty"`
+	DividendYield     float64    `json:"dividendYield,omitempty"` // in percent
+	ExDividendDate    *time.Time `json:"exDividendDate,omitempty"`
+	EarningsDate      *time.Time `json:"earningsDate,omitempty"`
+	EarningsDateEnd   *time.Time `json:"earningsDateEnd,omitempty"`
+	TargetEstimate    float64    `json:"targetEstimate,omitempty"`
+}
+
+// unitExponents are the suffixes Yahoo Finance uses for large numbers
+var unitExponents = map[byte]int{
+	'K': 3,
+	'M': 6,
+	'B': 9,
+	'T': 12,
+}
+
+// ParseAmount parses a number with an optional unit suffix, e.g. "2.93T" or "1.2M"
+func ParseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+	if exponent, ok := unitExponents[s[len(s)-1]]; ok {
+		// Parse with an exponent rather than multiplying so that "512.33B"
+		// yields exactly 512330000000
+		f, err := ParseNumber(fmt.Sprintf("%se%d", s[:len(s)-1], exponent))
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		return f, nil
+	}
+	return ParseNumber(s)
+}
+
+// parseStatistics reads the quote-statistics list of label/value pairs
+func parseStatistics(doc *goquery.Document) *Statistics {
+	list := doc.Find("[data-testid='quote-statistics'] li")
+	if list.Length() == 0 {
+		return nil
+	}
+
+	stats := &Statistics{}
+	list.Each(func(i int, s *goquery.Selection) {
+		label := strings.TrimSpace(s.Find(".label").First().Text())
+		value := strings.TrimSpace(s.Find(".value").First().Text())
+		if value == "" || value == "N/A" || value == "--" {
+			return
+		}
+		stats.set(label, value)
+	})
+	return stats
+}
+
+func (s *Statistics) set(label, value string) {
+	number := func() float64 {
+		f, _ := ParseAmount(value)
+		return f
+	}
+
+	switch {
+	case label == "Previous Close":
+		s.PreviousClose = number()
+	case label == "Open":
+		s.Open = number()
+	case label == "Day's Range":
+		s.DayRange = parseRange(value)
+	case label == "52 Week Range":
+		s.FiftyTwoWeekRange = parseRange(value)
+	case label == "Volume":
+		s.Volume = int64(number())
+	case strings.HasPrefix(label, "Avg. Volume"):
+		s.AvgVolume = int64(number())
+	case strings.HasPrefix(label, "Market Cap"):
+		s.MarketCap = number()
+	case strings.HasPrefix(label, "Beta"):
+		s.Beta = number()
+	case strings.HasPrefix(label, "PE Ratio"):
+		s.PERatio = number()
+	case strings.HasPrefix(label, "EPS"):
+		s.EPS = number()
+	case strings.HasPrefix(label, "Forward Dividend"):
+		// e.g. "1.00 (0.40%)"
+		amount, yield, _ := strings.Cut(value, "(")
+		s.ForwardDividend, _ = ParseAmount(amount)
+		s.DividendYield, _ = ParseNumber(yield)
+	case label == "Yield":
+		// Funds report only a yield, e.g. "1.23%"
+		s.DividendYield, _ = ParseNumber(value)
+	case label == "Ex-Dividend Date":
+		s.ExDividendDate = parseDate(value)
+	case label == "Earnings Date":
+		// Either a single date or an expected range, e.g. "Jan 30, 2025 - Feb 3, 2025"
+		start, end, isRange := strings.Cut(value, " - ")
+		s.EarningsDate = parseDate(start)
+		if isRange {
+			s.EarningsDateEnd = parseDate(end)
+		}
+	case strings.HasPrefix(label, "1y Target Est"):
+		s.TargetEstimate = number()
+	}
+}
+
+// parseRange parses a range such as "247.74 - 255.00"
+func parseRange(value string) *Range {
+	low, high, ok := strings.Cut(value, " - ")
+	if !ok {
+		return nil
+	}
+	l, err := ParseNumber(low)
+	if err != nil {
+		return nil
+	}
+	h, err := ParseNumber(high)
+	if err != nil {
+		return nil
+	}
+	return &Range{Low: l, High: h}
+}
+
+func parseDate(value string) *time.Time {
+	t, err := time.Parse("Jan 2, 2006", strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// String renders the statistics as aligned label/value lines, skipping missing values
+func (s Statistics) String() string {
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%-20s %s\n", label, value)
+		}
+	}
+
+	line("Previous Close", optionalFloat(s.PreviousClose))
+	line("Open", optionalFloat(s.Open))
+	line("Day's Range", formatRange(s.DayRange))
+	line("52 Week Range", formatRange(s.FiftyTwoWeekRange))
+	line("Volume", formatCount(s.Volume))
+	line("Avg. Volume", formatCount(s.AvgVolume))
+	line("Market Cap", FormatAmount(s.MarketCap))
+	line("Beta", optionalFloat(s.Beta))
+	line("P/E Ratio", optionalFloat(s.PERatio))
+	line("EPS", optionalFloat(s.EPS))
+	if s.ForwardDividend != 0 || s.DividendYield != 0 {
+		line("Dividend & Yield", fmt.Sprintf("%.2f (%.2f%%)", s.ForwardDividend, s.DividendYield))
+	}
+	line("Ex-Dividend Date", formatDate(s.ExDividendDate))
+	earnings := formatDate(s.EarningsDate)
+	if s.EarningsDateEnd != nil {
+		earnings += " - " + formatDate(s.EarningsDateEnd)
+	}
+	line("Earnings Date", earnings)
+	line("1y Target Est", optionalFloat(s.TargetEstimate))
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FormatAmount renders a large number with a unit suffix, e.g. 3847000000000 as "3.85T"
+func FormatAmount(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	for _, unit := range []byte{'T', 'B', 'M', 'K'} {
+		if multiplier := math.Pow10(unitExponents[unit]); f >= multiplier {
+			return fmt.Sprintf("%.2f%c", f/multiplier, unit)
+		}
+	}
+	return formatFloat(f)
+}
+
+func formatRange(r *Range) string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f - %.2f", r.Low, r.High)
+}
+
+func formatCount(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
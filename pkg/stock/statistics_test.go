@@ -0,0 +1,81 @@
+package stock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStatistics(t *testing.T) {
+	pinNow(t, time.Date(2024, 12, 22, 12, 0, 0, 0, time.UTC))
+	s := parseFixture(t, "quote_aapl.html", "AAPL").Stats
+	if s == nil {
+		t.Fatal("expected statistics")
+	}
+
+	if s.PreviousClose != 252.61 || s.Open != 248.04 {
+		t.Errorf("previous close/open = %v/%v", s.PreviousClose, s.Open)
+	}
+	if s.DayRange == nil || *s.DayRange != (Range{Low: 247.74, High: 255}) {
+		t.Errorf("day range = %+v", s.DayRange)
+	}
+	if s.FiftyTwoWeekRange == nil || *s.FiftyTwoWeekRange != (Range{Low: 164.08, High: 255}) {
+		t.Errorf("52 week range = %+v", s.FiftyTwoWeekRange)
+	}
+	if s.Volume != 147495267 || s.AvgVolume != 44977411 {
+		t.Errorf("volume/avg volume = %d/%d", s.Volume, s.AvgVolume)
+	}
+	if s.MarketCap != 3.847e12 {
+		t.Errorf("market cap = %v", s.MarketCap)
+	}
+	if s.Beta != 1.24 || s.PERatio != 41.86 || s.EPS != 6.08 || s.TargetEstimate != 247.36 {
+		t.Errorf("beta/pe/eps/target = %v/%v/%v/%v", s.Beta, s.PERatio, s.EPS, s.TargetEstimate)
+	}
+	if s.ForwardDividend != 1 || s.DividendYield != 0.4 {
+		t.Errorf("dividend/yield = %v/%v", s.ForwardDividend, s.DividendYield)
+	}
+	if s.ExDividendDate == nil || !s.ExDividendDate.Equal(time.Date(2024, 11, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ex-dividend date = %v", s.ExDividendDate)
+	}
+	if s.EarningsDate == nil || s.EarningsDateEnd == nil ||
+		s.EarningsDate.Format("2006-01-02") != "2025-01-30" || s.EarningsDateEnd.Format("2006-01-02") != "2025-02-03" {
+		t.Errorf("earnings dates = %v - %v", s.EarningsDate, s.EarningsDateEnd)
+	}
+
+	out := s.String()
+	for _, want := range []string{"Market Cap           3.85T", "Volume               147,495,267", "Dividend & Yield     1.00 (0.40%)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseStatisticsMissing(t *testing.T) {
+	pinNow(t, time.Date(2024, 12, 23, 12, 0, 0, 0, time.UTC))
+	if s := parseFixture(t, "quote_nokia_premarket.html", "NOK").Stats; s != nil {
+		t.Errorf("expected no statistics, got %+v", s)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := map[string]float64{
+		"2.93T":   2.93e12,
+		"1.2M":    1.2e6,
+		"512.33B": 512.33e9,
+		"15K":     15000,
+		"1,234.5": 1234.5,
+		" 0.75 ":  0.75,
+	}
+	for input, want := range tests {
+		got, err := ParseAmount(input)
+		if err != nil || got != want {
+			t.Errorf("ParseAmount(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "N/A", "xT"} {
+		if _, err := ParseAmount(input); err == nil {
+			t.Errorf("ParseAmount(%q) should fail", input)
+		}
+	}
+}
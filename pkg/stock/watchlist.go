@@ -0,0 +1,86 @@
+package stock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Watchlists maps watchlist names to the symbols they contain
+type Watchlists map[string][]string
+
+// LoadWatchlists reads watchlists from a YAML file of the form
+//
+//	tech: [AAPL, MSFT, NVDA]
+//	nordic: [NOK, NOVO-B.CO]
+//
+// A missing file yields no watchlists.
+func LoadWatchlists(path string) (Watchlists, error) {
+	lists := make(Watchlists)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lists, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading watchlists: %v", err)
+	}
+
+	if err := yaml.Unmarshal(data, &lists); err != nil {
+		return nil, fmt.Errorf("error parsing watchlists file %s: %v", path, err)
+	}
+	return lists, nil
+}
+
+// Save writes the watchlists to a YAML file
+func (w Watchlists) Save(path string) error {
+	data, err := yaml.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("error encoding watchlists: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating watchlists directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing watchlists: %v", err)
+	}
+	return nil
+}
+
+// Get returns the symbols of a watchlist
+func (w Watchlists) Get(name string) ([]string, error) {
+	symbols, ok := w[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown watchlist %q (available: %s)", name, strings.Join(w.Names(), ", "))
+	}
+	return symbols, nil
+}
+
+// Names returns the sorted watchlist names
+func (w Watchlists) Names() []string {
+	var names []string
+	for name := range w {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NormalizeSymbols upper-cases symbols and removes blanks and duplicates, keeping order
+func NormalizeSymbols(symbols []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, s := range symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		normalized = append(normalized, s)
+	}
+	return normalized
+}
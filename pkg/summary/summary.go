@@ -0,0 +1,173 @@
+// Package summary condenses documents into their most central sentences.
+//
+// Summaries are extractive: sentences are picked from the document as they
+// are, ranked with TextRank (Mihalcea and Tarau, 2004). Sentences are nodes
+// of a graph whose edges are weighted by the words two sentences share, and
+// PageRank over that graph favours sentences that have much in common with
+// many others. No model or external service is involved.
+package summary
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+const (
+	// damping is the PageRank damping factor
+	damping = 0.85
+	// maxIterations and tolerance bound the PageRank power iteration
+	maxIterations = 100
+	tolerance     = 1e-6
+	// window is how many of the following sentences each sentence is
+	// compared with. Shorter documents are compared in full; longer ones
+	// would need time and memory quadratic in their length.
+	window = 500
+)
+
+// Sentence is a sentence picked for a summary
+type Sentence struct {
+	// Index is the position of the sentence in the document
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	// Start is the time the sentence starts in seconds, for timed documents
+	Start *float64 `json:"start,omitempty"`
+	// Score is the TextRank score of the sentence; scores of a document sum to 1
+	Score float64 `json:"score"`
+}
+
+// Summary is the result of Summarize
+type Summary struct {
+	// Sentences is the number of sentences in the document
+	Sentences int `json:"sentences"`
+	// Summary are the picked sentences in document order
+	Summary []Sentence `json:"summary"`
+}
+
+// Summarize picks the n most central sentences of a document and returns
+// them in document order; a negative n keeps them all. The language selects
+// the stop words, which are ignored when comparing sentences; English words
+// are also stemmed. The document's detected or declared language takes
+// precedence over lang.
+func Summarize(doc *words.Document, lang string, n int) *Summary {
+	lang = doc.Language(lang)
+	sentences := doc.Sentences()
+	ranked := Rank(sentences, lang)
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	if n >= 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].Index < ranked[j].Index })
+
+	return &Summary{Sentences: len(sentences), Summary: ranked}
+}
+
+// edge links a sentence to a similar one
+type edge struct {
+	to     int
+	weight float64
+}
+
+// Rank scores sentences with TextRank and returns them in their original
+// order. Sentences further apart than the window are not compared, so the
+// graph of a long document is sparse.
+func Rank(sentences []words.Sentence, lang string) []Sentence {
+	terms := make([]map[string]bool, len(sentences))
+	for i, s := range sentences {
+		terms[i] = contentTerms(s.Text, lang)
+	}
+
+	// edges[i] are the sentences similar to sentence i
+	edges := make([][]edge, len(sentences))
+	totals := make([]float64, len(sentences))
+	for i := range sentences {
+		for j := i + 1; j < len(sentences) && j <= i+window; j++ {
+			w := similarity(terms[i], terms[j])
+			if w == 0 {
+				continue
+			}
+			edges[i] = append(edges[i], edge{to: j, weight: w})
+			edges[j] = append(edges[j], edge{to: i, weight: w})
+			totals[i] += w
+			totals[j] += w
+		}
+	}
+
+	scores := pageRank(edges, totals)
+	ranked := make([]Sentence, len(sentences))
+	for i, s := range sentences {
+		ranked[i] = Sentence{Index: i, Text: s.Text, Start: s.Start, Score: math.Round(scores[i]*1e4) / 1e4}
+	}
+	return ranked
+}
+
+// contentTerms returns the stems of the words of a text that are not stop words
+func contentTerms(text, lang string) map[string]bool {
+	stop := words.StopWords(lang)
+	terms := make(map[string]bool)
+	for _, token := range words.Tokenize(text) {
+		if !stop[token] {
+			terms[words.Stem(token, lang)] = true
+		}
+	}
+	return terms
+}
+
+// similarity is the TextRank similarity of two sentences: the number of
+// terms they share, normalised by the logs of their lengths so that long
+// sentences are not favoured just for being long. One is added to the
+// lengths, since the log of a single-term sentence would be zero.
+func similarity(a, b map[string]bool) float64 {
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / (math.Log(float64(len(a))+1) + math.Log(float64(len(b))+1))
+}
+
+// pageRank computes weighted PageRank scores normalised to sum to 1.
+// Sentences sharing nothing with the others only get the random jump share.
+// Similarity is symmetric, so the edges of a sentence are also its links in.
+func pageRank(edges [][]edge, totals []float64) []float64 {
+	n := len(edges)
+	scores := make([]float64, n)
+	if n == 0 {
+		return scores
+	}
+	for i := range scores {
+		scores[i] = 1 / float64(n)
+	}
+
+	next := make([]float64, n)
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		delta := 0.0
+		for i := range next {
+			sum := 0.0
+			for _, e := range edges[i] {
+				sum += e.weight / totals[e.to] * scores[e.to]
+			}
+			next[i] = (1-damping)/float64(n) + damping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores, next = next, scores
+		if delta < tolerance {
+			break
+		}
+	}
+
+	total := 0.0
+	for _, s := range scores {
+		total += s
+	}
+	for i := range scores {
+		scores[i] /= total
+	}
+	return scores
+}
@@ -0,0 +1,71 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/mjlefevre/sanoja/pkg/words"
+)
+
+func TestSummarize(t *testing.T) {
+	doc := &words.Document{
+		Timed: true,
+		Segments: []words.Segment{
+			{Text: "Saunas are hot rooms for bathing.", Start: 0, Duration: 3},
+			{Text: "Finnish saunas are heated with wood stoves.", Start: 3, Duration: 3},
+			{Text: "The weather was nice yesterday.", Start: 6, Duration: 3},
+			{Text: "Many Finnish homes have a sauna", Start: 9, Duration: 2},
+			{Text: "with a wood stove.", Start: 11, Duration: 2},
+			{Text: "Bathing in a hot sauna is a Finnish tradition.", Start: 13, Duration: 3},
+		},
+	}
+
+	s := Summarize(doc, "en", 3)
+	if s.Sentences != 5 || len(s.Summary) != 3 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	for _, sentence := range s.Summary {
+		if sentence.Text == "The weather was nice yesterday." {
+			t.Errorf("off-topic sentence picked: %+v", s.Summary)
+		}
+	}
+	if first := s.Summary[0]; first.Index >= s.Summary[1].Index || first.Start == nil {
+		t.Errorf("summary should be in document order with timestamps: %+v", s.Summary)
+	}
+	if last := s.Summary[2]; last.Text != "Bathing in a hot sauna is a Finnish tradition." || *last.Start != 13 {
+		t.Errorf("unexpected last sentence: %+v", last)
+	}
+
+	all := Summarize(doc, "en", -1)
+	total := 0.0
+	for _, sentence := range all.Summary {
+		total += sentence.Score
+	}
+	if len(all.Summary) != 5 || total < 0.999 || total > 1.001 {
+		t.Errorf("scores of all sentences should sum to 1, got %v: %+v", total, all.Summary)
+	}
+	for _, sentence := range all.Summary {
+		if sentence.Index != 2 && sentence.Score <= all.Summary[2].Score {
+			t.Errorf("a sentence sharing nothing should rank lowest: %+v", all.Summary)
+		}
+	}
+
+	if s := Summarize(&words.Document{}, "en", 3); s.Sentences != 0 || len(s.Summary) != 0 {
+		t.Errorf("unexpected summary of an empty document: %+v", s)
+	}
+}
+
+func TestRankLongDocument(t *testing.T) {
+	sentences := make([]words.Sentence, 3*window)
+	for i := range sentences {
+		sentences[i] = words.Sentence{Text: "Finnish saunas are heated with wood stoves."}
+	}
+
+	ranked := Rank(sentences, "en")
+	if len(ranked) != len(sentences) {
+		t.Fatalf("got %d sentences, want %d", len(ranked), len(sentences))
+	}
+	// Identical sentences only differ in how many others are in their window
+	if first, middle := ranked[0].Score, ranked[len(ranked)/2].Score; first >= middle {
+		t.Errorf("first sentence scored %v, want less than the middle one's %v", first, middle)
+	}
+}
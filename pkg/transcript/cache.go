@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithCacheDir caches fetched transcripts as JSON files in dir, so that
+// repeated requests for the same video do not hit YouTube again
+func WithCacheDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.cacheDir = dir
+	}
+}
+
+// cacheVersion names the format of the cache files. Files of other formats
+// are in other directories and never read.
+const cacheVersion = "v2"
+
+// cachePath returns the cache file for a video and language preference
+func (c *Client) cachePath(videoID string, languageCodes []string) string {
+	name := videoID
+	if len(languageCodes) > 0 {
+		name += "." + strings.Join(languageCodes, "+")
+	}
+	return filepath.Join(c.cacheDir, "transcripts", cacheVersion, filepath.Base(name)+".json")
+}
+
+// cachedTranscript is the content of a cache file
+type cachedTranscript struct {
+	Track     Transcript        `json:"track"`
+	Available []Transcript      `json:"available"`
+	Entries   []TranscriptEntry `json:"entries"`
+}
+
+func (c *Client) readCache(videoID string, languageCodes []string) (cachedTranscript, bool) {
+	if c.cacheDir == "" {
+		return cachedTranscript{}, false
+	}
+
+	data, err := os.ReadFile(c.cachePath(videoID, languageCodes))
+	if err != nil {
+		return cachedTranscript{}, false
+	}
+
+	var cached cachedTranscript
+	if err := json.Unmarshal(data, &cached); err != nil {
+		log.Printf("Ignoring corrupt transcript cache for %s: %v", videoID, err)
+		return cachedTranscript{}, false
+	}
+	return cached, true
+}
+
+func (c *Client) writeCache(videoID string, languageCodes []string, cached cachedTranscript) {
+	if c.cacheDir == "" {
+		return
+	}
+
+	path := c.cachePath(videoID, languageCodes)
+	data, err := json.Marshal(cached)
+	if err != nil {
+		log.Printf("Error encoding transcript cache: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Printf("Error creating transcript cache directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Printf("Error writing transcript cache: %v", err)
+	}
+}
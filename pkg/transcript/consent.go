@@ -0,0 +1,108 @@
+package transcript
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const consentHost = "consent.youtube.com"
+
+var playabilityStatusRe = regexp.MustCompile(`"playabilityStatus":\{"status":"(LOGIN_REQUIRED|AGE_CHECK_REQUIRED|CONTENT_CHECK_REQUIRED)"(?:,"reason":"(.*?)")?`)
+
+// isConsentPage reports whether the response is YouTube's cookie consent interstitial
+func isConsentPage(pageURL *url.URL, body string) bool {
+	if pageURL != nil && pageURL.Host == consentHost {
+		return true
+	}
+	return strings.Contains(body, `action="https://`+consentHost+`/save"`)
+}
+
+// loginRequired reports whether the watch page asks the viewer to sign in,
+// which is how YouTube gates age-restricted videos
+func loginRequired(body string) (string, bool) {
+	matches := playabilityStatusRe.FindStringSubmatch(body)
+	if matches == nil {
+		return "", false
+	}
+	return matches[2], true
+}
+
+// acceptConsent submits the consent form found on the interstitial page so that
+// the resulting consent cookies are stored in the client's cookie jar
+func (c *Client) acceptConsent(pageURL *url.URL, body string) error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error parsing consent page: %v", err)
+	}
+
+	form := findConsentForm(doc)
+	if form == nil {
+		return fmt.Errorf("could not find consent form")
+	}
+
+	action, _ := form.Attr("action")
+	actionURL, err := url.Parse(action)
+	if err != nil {
+		return fmt.Errorf("invalid consent form action %q: %v", action, err)
+	}
+	if pageURL != nil {
+		actionURL = pageURL.ResolveReference(actionURL)
+	}
+
+	values := url.Values{}
+	form.Find("input[type='hidden']").Each(func(i int, s *goquery.Selection) {
+		name, ok := s.Attr("name")
+		if !ok || name == "" {
+			return
+		}
+		value, _ := s.Attr("value")
+		values.Add(name, value)
+	})
+
+	req, err := http.NewRequest("POST", actionURL.String(), strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating consent request: %v", err)
+	}
+	c.browser.Apply(req)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error submitting consent form: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("consent form rejected with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// findConsentForm picks the consent form to submit. The page offers both
+// "Reject all" and "Accept all" forms; rejecting is preferred since it is
+// enough to reach the video without opting in to personalisation.
+func findConsentForm(doc *goquery.Document) *goquery.Selection {
+	forms := doc.Find("form").FilterFunction(func(i int, s *goquery.Selection) bool {
+		action, _ := s.Attr("action")
+		return strings.Contains(action, consentHost) || strings.HasPrefix(action, "/save")
+	})
+	if forms.Length() == 0 {
+		return nil
+	}
+
+	reject := forms.FilterFunction(func(i int, s *goquery.Selection) bool {
+		value, _ := s.Find("input[name='set_eom']").Attr("value")
+		return value == "true"
+	})
+	if reject.Length() > 0 {
+		return reject.First()
+	}
+
+	return forms.First()
+}
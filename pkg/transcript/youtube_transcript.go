@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/mjlefevre/sanoja/internal/browser"
+	"github.com/mjlefevre/sanoja/internal/cassette"
 )
 
 // Error types
@@ -40,9 +41,39 @@ func (e ErrTranscriptsDisabled) Error() string {
 	return fmt.Sprintf("Transcripts are disabled for video %s", e.VideoID)
 }
 
+type ErrConsentRequired struct {
+	VideoID string
+}
+
+func (e ErrConsentRequired) Error() string {
+	return fmt.Sprintf("Could not get past the cookie consent page for video %s", e.VideoID)
+}
+
+type ErrLoginRequired struct {
+	VideoID string
+	Reason  string
+}
+
+func (e ErrLoginRequired) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("Video %s requires sign-in: %s (try --cookies with an authenticated cookies.txt)", e.VideoID, e.Reason)
+	}
+	return fmt.Sprintf("Video %s requires sign-in (try --cookies with an authenticated cookies.txt)", e.VideoID)
+}
+
 // Client represents the YouTube Transcript API client
 type Client struct {
 	httpClient *http.Client
+	browser    *browser.Session
+	cacheDir   string
+	// jar replaces the cookie jar of the HTTP client when set
+	jar http.CookieJar
+	// proxy, record and replay change the transport of the HTTP client when set
+	proxy  *url.URL
+	record string
+	replay string
+	// err is a configuration error that fails every request of the client
+	err error
 }
 
 // Transcript represents a single transcript
@@ -55,70 +86,248 @@ type Transcript struct {
 
 // TranscriptEntry represents a single entry in the transcript
 type TranscriptEntry struct {
-	Text     string
-	Start    float64
-	Duration float64
+	Text     string  `json:"text"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
 }
 
 // NewClient creates a new YouTube Transcript API client
 func NewClient(options ...ClientOption) *Client {
 	c := &Client{
 		httpClient: &http.Client{},
+		browser:    browser.NewProfileSession(browser.DefaultProfile()),
 	}
 	for _, opt := range options {
 		opt(c)
 	}
+
+	// Set up the transport and cookie jar on a copy of the HTTP client, once,
+	// whatever the order of the options, so that a client shared through
+	// WithHTTPClient is left untouched, a later WithHTTPClient cannot undo
+	// WithReplay, and concurrent requests never race to create a jar. The
+	// consent cookies must survive until the watch page is fetched again.
+	httpClient := *c.httpClient
+	if c.proxy != nil {
+		httpClient.Transport = &http.Transport{Proxy: http.ProxyURL(c.proxy)}
+	}
+	switch {
+	case c.record != "" && c.replay != "":
+		c.setErr(fmt.Errorf("cannot record and replay at the same time"))
+	case c.record != "":
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		recorder, err := cassette.New(c.record, cassette.ModeRecord, cassette.WithTransport(transport))
+		if err != nil {
+			c.setErr(fmt.Errorf("error creating recorder: %v", err))
+		}
+		httpClient.Transport = recorder
+	case c.replay != "":
+		recorder, err := cassette.New(c.replay, cassette.ModeReplay)
+		if err != nil {
+			c.setErr(fmt.Errorf("error loading cassette: %v", err))
+		}
+		httpClient.Transport = recorder
+	}
+	if c.jar != nil {
+		httpClient.Jar = c.jar
+	}
+	if httpClient.Jar == nil {
+		jar, err := browser.NewCookieJar("")
+		if err != nil {
+			c.setErr(err)
+		}
+		httpClient.Jar = jar
+	}
+	if c.err != nil {
+		httpClient.Transport = errorTransport{c.err}
+	}
+	c.httpClient = &httpClient
 	return c
 }
 
+// setErr records the first configuration error of the client
+func (c *Client) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
 // ClientOption defines a function to configure the Client
 type ClientOption func(*Client)
 
-// WithProxy sets a proxy for the HTTP client
+// WithProxy sets a proxy for the HTTP client. If the proxy URL is invalid,
+// every request of the client fails with the error.
 func WithProxy(proxyURLStr string) ClientOption {
 	return func(c *Client) {
 		parsedURL, err := url.Parse(proxyURLStr)
 		if err != nil {
-			log.Printf("Error parsing proxy URL: %v", err)
+			c.setErr(fmt.Errorf("invalid proxy URL %q: %v", proxyURLStr, err))
 			return
 		}
-		c.httpClient.Transport = &http.Transport{
-			Proxy: http.ProxyURL(parsedURL),
+		c.proxy = parsedURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRecording saves every HTTP interaction of the client to the cassette directory dir.
+// If the recorder cannot be created, every request of the client fails with the error.
+func WithRecording(dir string) ClientOption {
+	return func(c *Client) {
+		c.record = dir
+	}
+}
+
+// WithReplay answers all HTTP requests of the client from the cassette directory dir,
+// without touching the network, whatever client WithHTTPClient sets. If the cassette
+// cannot be loaded, every request of the client fails with the error rather than
+// going to the network.
+func WithReplay(dir string) ClientOption {
+	return func(c *Client) {
+		c.replay = dir
+	}
+}
+
+// WithBrowserSession sets the browser session that decides which headers are sent
+func WithBrowserSession(session *browser.Session) ClientOption {
+	return func(c *Client) {
+		c.browser = session
+	}
+}
+
+// WithCookieJar installs a cookie jar that persists cookies across requests
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(c *Client) {
+		c.jar = jar
+	}
+}
+
+// WithCookiesFile installs a cookie jar preloaded from a Netscape-format cookies.txt file.
+// This allows fetching transcripts for age-restricted videos with an authenticated session.
+// If the file cannot be loaded, every request of the client fails with the error.
+func WithCookiesFile(path string) ClientOption {
+	return func(c *Client) {
+		jar, err := browser.NewCookieJar(path)
+		if err != nil {
+			c.setErr(fmt.Errorf("error loading cookies: %v", err))
+			return
 		}
+		c.jar = jar
 	}
 }
 
+// errorTransport fails every request with err. Options that cannot be
+// applied install it, so that a misconfigured client fails instead of
+// quietly going ahead without what was asked for.
+type errorTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
 // GetTranscript fetches the transcript for a given video ID, preferring English if available
 func (c *Client) GetTranscript(videoID string) ([]TranscriptEntry, error) {
+	return c.GetTranscriptWithLanguages(videoID, []string{"en"}) // Matches 'en', 'en-US', 'en-GB', etc.
+}
+
+// GetTranscriptWithLanguages fetches the transcript in the first available language
+// from languageCodes, in order of preference. If none of them is available, it falls
+// back to the first transcript listed for the video.
+func (c *Client) GetTranscriptWithLanguages(videoID string, languageCodes []string) ([]TranscriptEntry, error) {
+	entries, _, err := c.GetTranscriptTrack(videoID, languageCodes)
+	return entries, err
+}
+
+// GetTranscriptTrack fetches the transcript like GetTranscriptWithLanguages and
+// also returns the track it was read from, so that callers know its declared
+// language.
+func (c *Client) GetTranscriptTrack(videoID string, languageCodes []string) ([]TranscriptEntry, Transcript, error) {
+	if cached, ok := c.readCache(videoID, languageCodes); ok {
+		return cached.Entries, cached.Track, nil
+	}
+
+	cached, err := c.fetchTranscriptTrack(videoID, languageCodes)
+	if err != nil {
+		return nil, Transcript{}, err
+	}
+	return cached.Entries, cached.Track, nil
+}
+
+// fetchTranscriptTrack fetches the transcript in the first available
+// language from languageCodes together with the list of all tracks, and
+// caches them
+func (c *Client) fetchTranscriptTrack(videoID string, languageCodes []string) (cachedTranscript, error) {
 	videoInfo, err := c.fetchVideoInfo(videoID)
 	if err != nil {
-		return nil, err
+		return cachedTranscript{}, err
 	}
 
 	transcripts, err := extractTranscriptData(videoInfo)
 	if err != nil {
-		return nil, err
+		return cachedTranscript{}, err
 	}
 
 	if len(transcripts) == 0 {
-		return nil, ErrNoTranscriptFound{VideoID: videoID}
+		return cachedTranscript{}, ErrNoTranscriptFound{VideoID: videoID}
 	}
 
-	// Try to find English transcript first
-	var selectedTranscript Transcript
-	for _, t := range transcripts {
-		if strings.HasPrefix(t.LanguageCode, "en") { // Matches 'en', 'en-US', 'en-GB', etc.
-			selectedTranscript = t
-			break
-		}
+	track := selectTranscript(transcripts, languageCodes)
+	entries, err := c.fetchTranscript(track)
+	if err != nil {
+		return cachedTranscript{}, err
 	}
 
-	// If no English transcript found, fall back to the first available one
-	if selectedTranscript.BaseURL == "" {
-		selectedTranscript = transcripts[0]
+	cached := cachedTranscript{Track: track, Available: transcripts, Entries: entries}
+	c.writeCache(videoID, languageCodes, cached)
+	return cached, nil
+}
+
+// VideoTranscript is a transcript together with the track it was read from
+// and the other tracks of the video
+type VideoTranscript struct {
+	VideoID string
+	// Track is the transcript the entries were read from
+	Track Transcript
+	// Available are all transcripts of the video
+	Available []Transcript
+	Entries   []TranscriptEntry
+}
+
+// GetVideoTranscript fetches the transcript in the first available language
+// from languageCodes like GetTranscriptWithLanguages, and also reports which
+// track was picked and which others exist
+func (c *Client) GetVideoTranscript(videoID string, languageCodes []string) (*VideoTranscript, error) {
+	cached, ok := c.readCache(videoID, languageCodes)
+	if !ok {
+		var err error
+		if cached, err = c.fetchTranscriptTrack(videoID, languageCodes); err != nil {
+			return nil, err
+		}
 	}
+	return &VideoTranscript{VideoID: videoID, Track: cached.Track, Available: cached.Available, Entries: cached.Entries}, nil
+}
 
-	return c.fetchTranscript(selectedTranscript)
+// selectTranscript picks the first transcript matching the preferred language codes,
+// falling back to the first available one
+func selectTranscript(transcripts []Transcript, languageCodes []string) Transcript {
+	for _, code := range languageCodes {
+		for _, t := range transcripts {
+			if strings.HasPrefix(t.LanguageCode, code) {
+				return t
+			}
+		}
+	}
+	return transcripts[0]
 }
 
 // GetTranscriptString fetches the transcript and returns it as a single string
@@ -143,34 +352,69 @@ func ConcatenateTranscript(entries []TranscriptEntry) string {
 }
 
 func (c *Client) fetchVideoInfo(videoID string) (string, error) {
+	// Report a misconfigured client as such rather than as an unavailable video
+	if c.err != nil {
+		return "", c.err
+	}
 	if strings.TrimSpace(videoID) == "" {
 		return "", &ErrVideoUnavailable{VideoID: videoID}
 	}
 
+	body, finalURL, err := c.fetchWatchPage(videoID)
+	if err != nil {
+		return "", err
+	}
+
+	// From EU networks YouTube redirects to a consent interstitial first
+	if isConsentPage(finalURL, body) {
+		if err := c.acceptConsent(finalURL, body); err != nil {
+			log.Printf("Error submitting consent form: %v", err)
+			return "", &ErrConsentRequired{VideoID: videoID}
+		}
+
+		body, finalURL, err = c.fetchWatchPage(videoID)
+		if err != nil {
+			return "", err
+		}
+		if isConsentPage(finalURL, body) {
+			return "", &ErrConsentRequired{VideoID: videoID}
+		}
+	}
+
+	if reason, ok := loginRequired(body); ok {
+		return "", &ErrLoginRequired{VideoID: videoID, Reason: reason}
+	}
+
+	return body, nil
+}
+
+// fetchWatchPage fetches the watch page and returns its body along with the
+// URL it was served from after following redirects
+func (c *Client) fetchWatchPage(videoID string) (string, *url.URL, error) {
 	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
 	req, err := http.NewRequest("GET", videoURL, nil)
 	if err != nil {
-		return "", &ErrVideoUnavailable{VideoID: videoID}
+		return "", nil, &ErrVideoUnavailable{VideoID: videoID}
 	}
 
-	browser.SetDefaultHeaders(req)
+	c.browser.Apply(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", &ErrVideoUnavailable{VideoID: videoID}
+		return "", nil, &ErrVideoUnavailable{VideoID: videoID}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", &ErrVideoUnavailable{VideoID: videoID}
+		return "", nil, &ErrVideoUnavailable{VideoID: videoID}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return string(body), nil
+	return string(body), resp.Request.URL, nil
 }
 
 func extractTranscriptData(videoInfo string) ([]Transcript, error) {
@@ -260,7 +504,7 @@ func (c *Client) fetchTranscript(transcript Transcript) ([]TranscriptEntry, erro
 		return nil, err
 	}
 
-	browser.SetDefaultHeaders(req)
+	c.browser.Apply(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -330,29 +574,53 @@ func (c *Client) ListAvailableTranscripts(videoID string) ([]Transcript, error)
 	return extractTranscriptData(videoInfo)
 }
 
-// FetchMultipleTranscripts fetches transcripts for multiple video IDs concurrently
-func (c *Client) FetchMultipleTranscripts(videoIDs []string) map[string][]TranscriptEntry {
-	results := make(map[string][]TranscriptEntry)
+// TranscriptResult is the outcome of fetching one video with FetchTranscripts
+type TranscriptResult struct {
+	VideoID string
+	Entries []TranscriptEntry
+	Err     error
+}
+
+// FetchTranscripts fetches the transcripts of several videos in the first
+// available of languageCodes, with at most concurrency requests in flight.
+// Results are returned in the order of videoIDs.
+func (c *Client) FetchTranscripts(videoIDs []string, languageCodes []string, concurrency int) []TranscriptResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]TranscriptResult, len(videoIDs))
+	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
-	for _, id := range videoIDs {
+	for i, id := range videoIDs {
 		wg.Add(1)
-		go func(id string) {
+		go func(i int, id string) {
 			defer wg.Done()
-			transcript, err := c.GetTranscript(id)
-			if err == nil {
-				mu.Lock()
-				results[id] = transcript
-				mu.Unlock()
-			}
-		}(id)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			entries, err := c.GetTranscriptWithLanguages(id, languageCodes)
+			results[i] = TranscriptResult{VideoID: id, Entries: entries, Err: err}
+		}(i, id)
 	}
 
 	wg.Wait()
 	return results
 }
 
+// FetchMultipleTranscripts fetches English transcripts for multiple video IDs
+// concurrently. Videos whose transcript could not be fetched are left out.
+func (c *Client) FetchMultipleTranscripts(videoIDs []string) map[string][]TranscriptEntry {
+	results := make(map[string][]TranscriptEntry)
+	for _, result := range c.FetchTranscripts(videoIDs, []string{"en"}, len(videoIDs)) {
+		if result.Err == nil {
+			results[result.VideoID] = result.Entries
+		}
+	}
+	return results
+}
+
 // ExtractVideoID extracts the video ID from various YouTube URL formats or returns the ID directly.
 // It supports full youtube.com URLs, short youtu.be URLs, and direct video IDs.
 func ExtractVideoID(input string) string {
@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewClientCopiesHTTPClient(t *testing.T) {
+	shared := &http.Client{}
+	c := NewClient(WithHTTPClient(shared))
+	if shared.Jar != nil {
+		t.Error("the shared HTTP client should not get a cookie jar")
+	}
+	if c.httpClient == shared || c.httpClient.Jar == nil {
+		t.Error("the client should use a copy of the HTTP client with a cookie jar")
+	}
+}
+
+func TestWithCookiesFileError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.txt")
+	// The error must survive options given after it
+	c := NewClient(WithCookiesFile(path), WithHTTPClient(&http.Client{}))
+	_, err := c.GetTranscript("k82RwXqZHY8")
+	if err == nil || !strings.Contains(err.Error(), "error loading cookies") {
+		t.Errorf("expected the cookies error, got %v", err)
+	}
+}
+
+func TestWithProxyError(t *testing.T) {
+	c := NewClient(WithProxy("http://[::1"))
+	_, err := c.GetTranscript("k82RwXqZHY8")
+	if err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+		t.Errorf("expected the proxy error instead of a direct connection, got %v", err)
+	}
+}
+
+func TestWithReplayFailsClosed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "001_get_www.youtube.com_watch.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(WithReplay(dir))
+	_, err := c.GetTranscript("k82RwXqZHY8")
+	if err == nil || !strings.Contains(err.Error(), "error loading cassette") {
+		t.Errorf("expected the cassette error instead of a network request, got %v", err)
+	}
+	if _, err := c.httpClient.Get("http://127.0.0.1:1/"); err == nil || !strings.Contains(err.Error(), "error loading cassette") {
+		t.Errorf("expected every request to fail with the cassette error, got %v", err)
+	}
+}
+
+// failingTransport counts requests and fails them
+type failingTransport struct{ requests int }
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	f.requests++
+	return nil, errors.New("no network in tests")
+}
+
+func TestGetVideoTranscriptCache(t *testing.T) {
+	transport := &failingTransport{}
+	c := NewClient(WithHTTPClient(&http.Client{Transport: transport}), WithCacheDir(t.TempDir()))
+	track := Transcript{LanguageCode: "fi", Language: "Finnish"}
+	available := []Transcript{{LanguageCode: "en", Language: "English"}, track}
+	c.writeCache("k82RwXqZHY8", []string{"fi"}, cachedTranscript{
+		Track:     track,
+		Available: available,
+		Entries:   []TranscriptEntry{{Text: "Hei kaikki", Start: 0, Duration: 2}},
+	})
+
+	vt, err := c.GetVideoTranscript("k82RwXqZHY8", []string{"fi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 0 {
+		t.Errorf("made %d requests, want the transcript read from the cache", transport.requests)
+	}
+	if vt.Track.LanguageCode != "fi" || len(vt.Available) != 2 || len(vt.Entries) != 1 || vt.Entries[0].Text != "Hei kaikki" {
+		t.Errorf("unexpected transcript: %+v", vt)
+	}
+
+}
+
+func TestWithReplayOptionOrder(t *testing.T) {
+	replay := filepath.Join("..", "..", "cmd", "testdata", "cassettes", "ytt")
+	for name, options := range map[string]func(*http.Client) []ClientOption{
+		"replay first": func(h *http.Client) []ClientOption { return []ClientOption{WithReplay(replay), WithHTTPClient(h)} },
+		"client first": func(h *http.Client) []ClientOption { return []ClientOption{WithHTTPClient(h), WithReplay(replay)} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			transport := &failingTransport{}
+			c := NewClient(options(&http.Client{Transport: transport})...)
+			entries, err := c.GetTranscript("k82RwXqZHY8")
+			if err != nil || len(entries) == 0 || transport.requests != 0 {
+				t.Errorf("got %d entries, %v after %d live requests, want the cassette only", len(entries), err, transport.requests)
+			}
+		})
+	}
+
+	c := NewClient(WithReplay(replay), WithRecording(t.TempDir()))
+	if _, err := c.GetTranscript("k82RwXqZHY8"); err == nil || !strings.Contains(err.Error(), "cannot record and replay") {
+		t.Errorf("expected recording and replaying together to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package wiki
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Article is the structured form of a Wikipedia article
+type Article struct {
+	Title          string `json:"title"`
+	Lang           string `json:"lang"`
+	URL            string `json:"url"`
+	RedirectedFrom string `json:"redirectedFrom,omitempty"`
+	// Summary is the text of the lead section, before the first heading
+	Summary       string         `json:"summary"`
+	Sections      []*Section     `json:"sections"`
+	Infobox       []InfoboxField `json:"infobox,omitempty"`
+	Categories    []string       `json:"categories,omitempty"`
+	Links         []Link         `json:"links,omitempty"`
+	ExternalLinks []Link         `json:"externalLinks,omitempty"`
+	References    []Reference    `json:"references,omitempty"`
+	Images        []Image        `json:"images,omitempty"`
+}
+
+// Section is a headed part of an article with its subsections
+type Section struct {
+	Title    string     `json:"title"`
+	Level    int        `json:"level"`
+	Text     string     `json:"text,omitempty"`
+	Sections []*Section `json:"sections,omitempty"`
+}
+
+// InfoboxField is one labelled row of the infobox
+type InfoboxField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Link is a link to another article or an external site
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Reference is an entry of the article's reference list
+type Reference struct {
+	ID   string   `json:"id"`
+	Text string   `json:"text"`
+	URLs []string `json:"urls,omitempty"`
+}
+
+// Image is an image shown in the article
+type Image struct {
+	URL     string `json:"url"`
+	Alt     string `json:"alt,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// namespaces are the title prefixes of non-article pages, in English and Finnish
+var namespaces = map[string]bool{
+	"category": true, "draft": true, "file": true, "help": true, "image": true, "module": true,
+	"portal": true, "special": true, "talk": true, "template": true, "user": true, "wikipedia": true,
+	"kategoria": true, "luokka": true, "ohje": true, "tiedosto": true, "toiminnot": true, "malline": true,
+}
+
+// Article parses the page into its structured form
+func (p *Page) Article() *Article {
+	a := &Article{
+		Title:          p.Title,
+		Lang:           p.Lang,
+		URL:            p.URL,
+		RedirectedFrom: p.RedirectedFrom,
+		Sections:       []*Section{},
+	}
+
+	a.Summary, a.Sections = p.sections()
+	a.Infobox = p.infobox()
+	a.Links = p.links()
+	a.ExternalLinks = p.externalLinks()
+	a.References = p.references()
+	a.Images = p.images()
+	if p.doc != nil {
+		p.doc.Find("#mw-normal-catlinks li a").Each(func(i int, s *goquery.Selection) {
+			a.Categories = append(a.Categories, strings.TrimSpace(s.Text()))
+		})
+	}
+
+	return a
+}
+
+// sections splits the article body at its headings into the lead text and a section tree
+func (p *Page) sections() (string, []*Section) {
+	var lead []string
+	var roots []*Section
+	var stack []*Section
+
+	p.Content.Children().Each(func(i int, s *goquery.Selection) {
+		if h := headingOf(s); h != nil {
+			section := &Section{Title: strings.TrimSpace(h.Text()), Level: int(goquery.NodeName(h)[1] - '0')}
+			for len(stack) > 0 && stack[len(stack)-1].Level >= section.Level {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				roots = append(roots, section)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Sections = append(parent.Sections, section)
+			}
+			stack = append(stack, section)
+			return
+		}
+
+		text := blockText(s)
+		if text == "" {
+			return
+		}
+		if len(stack) == 0 {
+			lead = append(lead, text)
+			return
+		}
+		current := stack[len(stack)-1]
+		if current.Text != "" {
+			current.Text += "\n\n"
+		}
+		current.Text += text
+	})
+
+	if roots == nil {
+		roots = []*Section{}
+	}
+	return strings.Join(lead, "\n\n"), roots
+}
+
+// headingOf returns the heading element of a section heading block, if s is one
+func headingOf(s *goquery.Selection) *goquery.Selection {
+	switch goquery.NodeName(s) {
+	case "h2", "h3", "h4", "h5", "h6":
+		return s
+	case "div":
+		if s.HasClass("mw-heading") {
+			if h := s.Find("h2, h3, h4, h5, h6").First(); h.Length() > 0 {
+				return h
+			}
+		}
+	}
+	return nil
+}
+
+// blockText returns the prose of a paragraph or list, one list item per line.
+// Tables, figures and other non-prose blocks yield no text.
+func blockText(s *goquery.Selection) string {
+	switch goquery.NodeName(s) {
+	case "p":
+		return collapse(s.Text())
+	case "ul", "ol":
+		var items []string
+		s.Find("li").Each(func(i int, li *goquery.Selection) {
+			// Nested lists are visited as items of their own
+			item := li.Clone()
+			item.Children().Filter("ul, ol").Remove()
+			if text := collapse(item.Text()); text != "" {
+				items = append(items, text)
+			}
+		})
+		return strings.Join(items, "\n")
+	}
+	return ""
+}
+
+func (p *Page) infobox() []InfoboxField {
+	var fields []InfoboxField
+	p.Content.Find("table.infobox").First().Find("tr").Each(func(i int, row *goquery.Selection) {
+		name := collapse(row.Find("th").First().Text())
+		value := collapse(row.Find("td").First().Text())
+		if name != "" && value != "" {
+			fields = append(fields, InfoboxField{Name: name, Value: value})
+		}
+	})
+	return fields
+}
+
+// links lists the distinct articles linked from the body, skipping special
+// pages, files, categories and links to articles that do not exist yet
+func (p *Page) links() []Link {
+	seen := make(map[string]bool)
+	var links []Link
+	p.Content.Find("a[href^='/wiki/']").Not(".new, .mw-file-description").Each(func(i int, s *goquery.Selection) {
+		title, ok := s.Attr("title")
+		if !ok || seen[title] {
+			return
+		}
+		if prefix, _, found := strings.Cut(title, ":"); found && namespaces[strings.ToLower(prefix)] {
+			return
+		}
+		seen[title] = true
+		href, _ := s.Attr("href")
+		links = append(links, Link{Title: title, URL: p.absolute(href)})
+	})
+	return links
+}
+
+func (p *Page) externalLinks() []Link {
+	var links []Link
+	p.Content.Find("a.external").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		links = append(links, Link{Title: collapse(s.Text()), URL: p.absolute(href)})
+	})
+	return links
+}
+
+func (p *Page) references() []Reference {
+	if p.raw == nil {
+		return nil
+	}
+	var refs []Reference
+	p.raw.Find("ol.references > li").Each(func(i int, s *goquery.Selection) {
+		id, _ := s.Attr("id")
+		ref := Reference{ID: id, Text: collapse(s.Find(".reference-text").Text())}
+		s.Find(".reference-text a.external").Each(func(i int, a *goquery.Selection) {
+			href, _ := a.Attr("href")
+			ref.URLs = append(ref.URLs, p.absolute(href))
+		})
+		refs = append(refs, ref)
+	})
+	return refs
+}
+
+func (p *Page) images() []Image {
+	var images []Image
+	p.Content.Find("img").Each(func(i int, s *goquery.Selection) {
+		src, ok := s.Attr("src")
+		if !ok {
+			return
+		}
+		alt, _ := s.Attr("alt")
+		caption := s.Closest("figure").Find("figcaption").First().Text()
+		if caption == "" {
+			caption = s.Closest("td").Find(".infobox-caption").First().Text()
+		}
+		images = append(images, Image{URL: p.absolute(src), Alt: alt, Caption: collapse(caption)})
+	})
+	return images
+}
+
+// absolute resolves a link relative to the article URL
+func (p *Page) absolute(href string) string {
+	base, err := url.Parse(p.URL)
+	if err != nil || base.Host == "" {
+		base = &url.URL{Scheme: "https", Host: p.Lang + ".wikipedia.org"}
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(u).String()
+}
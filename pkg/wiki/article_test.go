@@ -0,0 +1,73 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArticle(t *testing.T) {
+	page, err := newTestClient(t).Fetch("Saunas", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := page.Article()
+
+	if !strings.HasPrefix(a.Summary, "A sauna (/ˈsɔːnə/) is a room") || !strings.HasSuffix(a.Summary, "five and a half million.") {
+		t.Errorf("summary = %q", a.Summary)
+	}
+
+	var titles []string
+	for _, s := range a.Sections {
+		titles = append(titles, s.Title)
+	}
+	if strings.Join(titles, "|") != "History|Health effects|References|External links" {
+		t.Errorf("sections = %v", titles)
+	}
+	history := a.Sections[0]
+	if len(history.Sections) != 1 || history.Sections[0].Title != "Smoke sauna" || history.Sections[0].Level != 3 {
+		t.Fatalf("expected Smoke sauna below History, got %+v", history.Sections)
+	}
+	if !strings.HasSuffix(history.Sections[0].Text, "Types of heating include:\n\nWood-burning stoves\nElectric heaters, which are common in apartments\nGas stoves") {
+		t.Errorf("smoke sauna text = %q", history.Sections[0].Text)
+	}
+
+	wantInfobox := []InfoboxField{{"Origin", "Finland"}, {"Typical temperature", "70–100 °C"}}
+	if len(a.Infobox) != 2 || a.Infobox[0] != wantInfobox[0] || a.Infobox[1] != wantInfobox[1] {
+		t.Errorf("infobox = %+v", a.Infobox)
+	}
+
+	if strings.Join(a.Categories, "|") != "Saunas|Finnish culture" {
+		t.Errorf("categories = %v", a.Categories)
+	}
+
+	var links []string
+	for _, l := range a.Links {
+		links = append(links, l.Title)
+	}
+	if strings.Join(links, "|") != "Sauna (disambiguation)|Finland|Dry heat|Heat|Culture of Finland|Cardiovascular disease|Steam bath" {
+		t.Errorf("links = %v", links)
+	}
+	if a.Links[1].URL != "https://en.wikipedia.org/wiki/Finland" {
+		t.Errorf("link URL = %q", a.Links[1].URL)
+	}
+
+	if len(a.ExternalLinks) != 1 || a.ExternalLinks[0].URL != "https://www.saunasociety.org/" {
+		t.Errorf("external links = %+v", a.ExternalLinks)
+	}
+
+	if len(a.References) != 2 {
+		t.Fatalf("references = %+v", a.References)
+	}
+	if ref := a.References[0]; ref.ID != "cite_note-temp-1" || ref.Text != `"Sauna facts". Finnish Sauna Society.` ||
+		len(ref.URLs) != 1 || ref.URLs[0] != "https://www.sauna.fi/en/sauna-facts/" {
+		t.Errorf("first reference = %+v", ref)
+	}
+
+	want := []Image{
+		{"https://upload.wikimedia.org/wikipedia/commons/thumb/a/ab/Sauna_interior.jpg/250px-Sauna_interior.jpg", "A wooden sauna interior", "Interior of a Finnish sauna"},
+		{"https://upload.wikimedia.org/wikipedia/commons/thumb/c/cd/Old_smoke_sauna.jpg/220px-Old_smoke_sauna.jpg", "", "An old smoke sauna"},
+	}
+	if len(a.Images) != 2 || a.Images[0] != want[0] || a.Images[1] != want[1] {
+		t.Errorf("images = %+v", a.Images)
+	}
+}
@@ -0,0 +1,48 @@
+package wiki
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// clean removes the parts of an article body that are not article content
+func clean(content *goquery.Selection) *goquery.Selection {
+	content.Find("style").Remove()  // Remove style tags
+	content.Find("script").Remove() // Remove script tags
+	// Remove HTML comments
+	content.Contents().Each(func(i int, s *goquery.Selection) {
+		if html.CommentNode == s.Get(0).Type {
+			s.Remove()
+		}
+	})
+	content.Find(".mw-editsection").Remove()                          // Remove edit links
+	content.Find(".reference").Remove()                               // Remove reference numbers
+	content.Find(".reflist").Remove()                                 // Remove references section
+	content.Find(".navbox").Remove()                                  // Remove navigation boxes
+	content.Find(".mw-parser-output").RemoveClass("mw-parser-output") // Remove parser output class
+	return content
+}
+
+// HTML returns the cleaned article body as HTML
+func (p *Page) HTML() (string, error) {
+	out, err := p.Content.Html()
+	if err != nil {
+		return "", fmt.Errorf("error getting HTML: %v", err)
+	}
+	return out, nil
+}
+
+// Text returns the article's paragraphs as plain text separated by blank lines
+func (p *Page) Text() string {
+	var texts []string
+	p.Content.Find("p").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if text != "" {
+			texts = append(texts, text)
+		}
+	})
+	return strings.Join(texts, "\n\n")
+}
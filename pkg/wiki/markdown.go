@@ -0,0 +1,344 @@
+package wiki
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MarkdownOptions configures Page.Markdown
+type MarkdownOptions struct {
+	// AbsoluteLinks turns links relative to Wikipedia into absolute URLs
+	AbsoluteLinks bool
+}
+
+// skippedClasses are blocks left out of Markdown output: the infobox and
+// figures are available from the structured article, the rest is site chrome
+var skippedClasses = []string{"infobox", "navbox", "metadata", "ambox", "mw-empty-elt", "thumb", "toc", "sidebar"}
+
+// Markdown renders the cleaned article body as GitHub-flavored Markdown,
+// keeping section headings, lists, tables, emphasis and links
+func (p *Page) Markdown(opts MarkdownOptions) string {
+	r := &markdownRenderer{opts: opts}
+	if base, err := url.Parse(p.URL); err == nil && base.Host != "" {
+		r.base = base
+	}
+	for _, n := range p.Content.Nodes {
+		r.blocks(n)
+	}
+	return strings.TrimSpace(r.out.String()) + "\n"
+}
+
+type markdownRenderer struct {
+	opts MarkdownOptions
+	base *url.URL
+	out  strings.Builder
+
+	// headings not yet written because no content followed them so far
+	pending []heading
+}
+
+type heading struct {
+	level int
+	text  string
+}
+
+// block writes a block of Markdown separated from the previous one by a blank line
+func (r *markdownRenderer) block(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, h := range r.pending {
+		r.write(strings.Repeat("#", h.level) + " " + h.text)
+	}
+	r.pending = nil
+	r.write(text)
+}
+
+func (r *markdownRenderer) write(text string) {
+	if r.out.Len() > 0 {
+		r.out.WriteString("\n\n")
+	}
+	r.out.WriteString(text)
+}
+
+// heading queues a section heading. Headings are only written once content
+// follows, so sections emptied by cleanup, such as References, are dropped.
+func (r *markdownRenderer) heading(level int, text string) {
+	for len(r.pending) > 0 && r.pending[len(r.pending)-1].level >= level {
+		r.pending = r.pending[:len(r.pending)-1]
+	}
+	r.pending = append(r.pending, heading{level: level, text: text})
+}
+
+// blocks renders the block-level children of n
+func (r *markdownRenderer) blocks(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+}
+
+func (r *markdownRenderer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.block(r.inline(n))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if skipped(n) {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.heading(int(n.Data[1]-'0'), r.inlineChildren(n))
+	case atom.P:
+		r.block(r.inlineChildren(n))
+	case atom.Ul, atom.Ol:
+		r.block(r.list(n, 0))
+	case atom.Table:
+		r.block(r.table(n))
+	case atom.Blockquote:
+		var quote markdownRenderer
+		quote.opts, quote.base = r.opts, r.base
+		quote.blocks(n)
+		lines := strings.Split(strings.TrimSpace(quote.out.String()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		r.block(strings.Join(lines, "\n"))
+	case atom.Pre:
+		r.block("```\n" + strings.Trim(textContent(n), "\n") + "\n```")
+	case atom.Dl:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Dt:
+				r.block("**" + r.inlineChildren(c) + "**")
+			case atom.Dd:
+				r.block(r.inlineChildren(c))
+			}
+		}
+	case atom.Figure, atom.Img, atom.Meta, atom.Link, atom.Style, atom.Script:
+	default:
+		if hasClass(n, "hatnote") {
+			r.block("*" + r.inlineChildren(n) + "*")
+			return
+		}
+		if isInline(n) {
+			r.block(r.inline(n))
+			return
+		}
+		r.blocks(n)
+	}
+}
+
+// list renders a list, indenting nested lists below their items
+func (r *markdownRenderer) list(n *html.Node, depth int) string {
+	var lines []string
+	number := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		var text strings.Builder
+		var nested []string
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Ul || c.DataAtom == atom.Ol {
+				nested = append(nested, r.list(c, depth+1))
+				continue
+			}
+			text.WriteString(r.inline(c))
+		}
+
+		indent := strings.Repeat("  ", depth)
+		lines = append(lines, indent+marker+collapse(text.String()))
+		lines = append(lines, nested...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table renders a table as a GFM table, using the first row as the header
+func (r *markdownRenderer) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Tr:
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Th || cell.DataAtom == atom.Td {
+						text := strings.ReplaceAll(r.inlineChildren(cell), "|", `\|`)
+						row = append(row, text)
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	line := func(row []string) string {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		return "| " + strings.Join(row, " | ") + " |"
+	}
+
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+	lines := []string{line(rows[0]), line(separator)}
+	for _, row := range rows[1:] {
+		lines = append(lines, line(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *markdownRenderer) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(r.inline(c))
+	}
+	return collapse(b.String())
+}
+
+// inline renders inline content: text, emphasis, links and code
+func (r *markdownRenderer) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeMarkdown(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+	if skipped(n) {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.B, atom.Strong:
+		return wrap("**", r.inlineChildren(n))
+	case atom.I, atom.Em:
+		return wrap("*", r.inlineChildren(n))
+	case atom.Code:
+		return wrap("`", textContent(n))
+	case atom.Br:
+		return " "
+	case atom.A:
+		text := r.inlineChildren(n)
+		href := attr(n, "href")
+		if text == "" || href == "" || strings.HasPrefix(href, "#") {
+			return text
+		}
+		return "[" + text + "](" + r.link(href) + ")"
+	case atom.Img, atom.Style, atom.Script, atom.Figure:
+		return ""
+	}
+
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(r.inline(c))
+	}
+	return b.String()
+}
+
+func (r *markdownRenderer) link(href string) string {
+	if r.opts.AbsoluteLinks && r.base != nil {
+		if u, err := url.Parse(href); err == nil {
+			href = r.base.ResolveReference(u).String()
+		}
+	}
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(href)
+}
+
+// wrap surrounds text with a Markdown marker, keeping surrounding spaces outside
+func wrap(marker, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	return marker + trimmed + marker
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// collapse squeezes runs of whitespace into single spaces
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func skipped(n *html.Node) bool {
+	for _, class := range skippedClasses {
+		if hasClass(n, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInline reports whether an element is phrasing content rendered within a paragraph
+func isInline(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.A, atom.B, atom.Strong, atom.I, atom.Em, atom.Span, atom.Code, atom.Sup, atom.Sub, atom.Small, atom.Abbr, atom.Cite, atom.Br:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package wiki
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	page, err := newTestClient(t).Fetch("Saunas", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := page.Markdown(MarkdownOptions{})
+	for _, want := range []string{
+		"A **sauna** ([/ˈsɔːnə/](/wiki/Help:IPA/English)) is a room",
+		"make the bathers *perspire*.",
+		"## History\n\nThe oldest known saunas",
+		"### Smoke sauna",
+		"- Wood-burning stoves\n- Electric heaters, which are common in apartments\n- Gas stoves",
+		"1. Shower before entering\n2. Sit on the **upper bench**\n3. Cool down between rounds",
+		"| Type | Temperature | Humidity |\n| --- | --- | --- |\n| Finnish sauna | 80–100 °C | 10–20% |",
+		"- [North American Sauna Society](https://www.saunasociety.org/)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected %q in Markdown:\n%s", want, md)
+		}
+	}
+	for _, unwanted := range []string{"## References", "Interior of a Finnish sauna", "Hammam", "[1]", "var x"} {
+		if strings.Contains(md, unwanted) {
+			t.Errorf("Markdown should not contain %q", unwanted)
+		}
+	}
+
+	absolute := page.Markdown(MarkdownOptions{AbsoluteLinks: true})
+	if !strings.Contains(absolute, "[Steam bath](https://en.wikipedia.org/wiki/Steam_bath)") ||
+		!strings.Contains(absolute, "[Sauna (disambiguation)](https://en.wikipedia.org/wiki/Sauna_%28disambiguation%29)") {
+		t.Errorf("expected absolute links:\n%s", absolute)
+	}
+}
+
+func TestMarkdownNestedLists(t *testing.T) {
+	page := parseHTML(t, `<p>Kinds of <i>löyly</i>:</p>
+<ul><li>Dry<ul><li>Electric</li><li>Wood <b>fired</b></li></ul></li><li>Wet</li></ul>
+<table class="wikitable"><tr><th>a|b</th></tr><tr><td>1</td><td>2</td></tr></table>`)
+
+	want := "Kinds of *löyly*:\n\n- Dry\n  - Electric\n  - Wood **fired**\n- Wet\n\n| a\\|b |  |\n| --- | --- |\n| 1 | 2 |\n"
+	if md := page.Markdown(MarkdownOptions{}); md != want {
+		t.Errorf("Markdown =\n%q\nwant\n%q", md, want)
+	}
+}
@@ -0,0 +1,274 @@
+// Package wiki fetches Wikipedia articles by title, URL or at random,
+// in any language edition.
+package wiki
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultLanguage is the Wikipedia edition used when none is given
+const DefaultLanguage = "en"
+
+// randomTitle is the special page that redirects to a random article
+const randomTitle = "Special:Random"
+
+var languageRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ErrNotFound is returned when Wikipedia has no article with the given title
+type ErrNotFound struct {
+	Title string
+	Lang  string
+}
+
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("%s Wikipedia does not have an article titled %q", e.Lang, e.Title)
+}
+
+// Option is an article listed on a disambiguation page
+type Option struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ErrDisambiguation is returned when the title leads to a disambiguation page
+type ErrDisambiguation struct {
+	Title   string
+	Options []Option
+}
+
+func (e ErrDisambiguation) Error() string {
+	return fmt.Sprintf("%q is a disambiguation page with %d options; fetch one of them instead", e.Title, len(e.Options))
+}
+
+// Page is a fetched Wikipedia article
+type Page struct {
+	Title string
+	Lang  string
+	// URL is the canonical URL of the article
+	URL string
+	// RedirectedFrom is the title that redirected to this article, if any
+	RedirectedFrom string
+	// Content is the cleaned article body, without edit links, references,
+	// navigation boxes, styles and scripts
+	Content *goquery.Selection
+
+	doc *goquery.Document
+	// raw is the article body before cleanup, which still has the references
+	raw *goquery.Selection
+}
+
+// Client fetches Wikipedia articles
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// ClientOption defines a function to configure the Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used for all requests
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL serves every language edition from baseURL, mainly for testing
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// NewClient creates a new Wikipedia client
+func NewClient(options ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{},
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Fetch fetches an article by title or Wikipedia URL. The language of a URL
+// takes precedence over lang; an empty lang means DefaultLanguage.
+// Wikipedia redirects are followed and reported in Page.RedirectedFrom.
+func (c *Client) Fetch(titleOrURL, lang string) (*Page, error) {
+	title, lang, err := ParseArticle(titleOrURL, lang)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetch(title, lang)
+}
+
+// Random fetches a random article
+func (c *Client) Random(lang string) (*Page, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if !languageRe.MatchString(lang) {
+		return nil, fmt.Errorf("invalid Wikipedia language %q", lang)
+	}
+	return c.fetch(randomTitle, lang)
+}
+
+// ArticleURL returns the URL of an article in a language edition
+func ArticleURL(title, lang string) string {
+	return fmt.Sprintf("https://%s.wikipedia.org/wiki/%s", lang, escapeTitle(title))
+}
+
+// ParseArticle splits a title or Wikipedia URL into the article title and
+// language. Desktop, mobile and index.php?title= URLs are accepted.
+func ParseArticle(titleOrURL, lang string) (title string, language string, err error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	titleOrURL = strings.TrimSpace(titleOrURL)
+	if titleOrURL == "" {
+		return "", "", fmt.Errorf("empty article title")
+	}
+
+	if !strings.Contains(titleOrURL, "://") {
+		if !languageRe.MatchString(lang) {
+			return "", "", fmt.Errorf("invalid Wikipedia language %q", lang)
+		}
+		return normalizeTitle(titleOrURL), lang, nil
+	}
+
+	u, err := url.Parse(titleOrURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL %q: %v", titleOrURL, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	if !strings.HasSuffix(host, ".wikipedia.org") {
+		return "", "", fmt.Errorf("not a Wikipedia URL: %s", titleOrURL)
+	}
+	lang = strings.Split(host, ".")[0]
+
+	switch {
+	case strings.HasPrefix(u.Path, "/wiki/"):
+		title = strings.TrimPrefix(u.Path, "/wiki/")
+	case u.Query().Get("title") != "":
+		title = u.Query().Get("title")
+	default:
+		return "", "", fmt.Errorf("no article title in URL %s", titleOrURL)
+	}
+	return normalizeTitle(title), lang, nil
+}
+
+func (c *Client) fetch(title, lang string) (*Page, error) {
+	pageURL := ArticleURL(title, lang)
+	if c.baseURL != "" {
+		pageURL = c.baseURL + "/wiki/" + escapeTitle(title)
+	}
+
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound{Title: title, Lang: lang}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, pageURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing page: %v", err)
+	}
+
+	page, err := parsePage(doc, lang)
+	if err != nil {
+		return nil, err
+	}
+	if page.URL == "" {
+		page.URL = resp.Request.URL.String()
+	}
+
+	if isDisambiguation(doc) {
+		return nil, ErrDisambiguation{Title: page.Title, Options: disambiguationOptions(page.Content)}
+	}
+
+	return page, nil
+}
+
+func parsePage(doc *goquery.Document, lang string) (*Page, error) {
+	title := strings.TrimSpace(doc.Find("#firstHeading").First().Text())
+	if title == "" {
+		return nil, fmt.Errorf("could not find article title")
+	}
+
+	bodyContent := doc.Find("#bodyContent").First()
+	if bodyContent.Length() == 0 {
+		return nil, fmt.Errorf("could not find article body content")
+	}
+
+	content := bodyContent.Find("#mw-content-text .mw-parser-output").First()
+	if content.Length() == 0 {
+		return nil, fmt.Errorf("could not find article content")
+	}
+
+	canonical, _ := doc.Find("link[rel='canonical']").Attr("href")
+
+	return &Page{
+		Title:          title,
+		Lang:           lang,
+		URL:            canonical,
+		RedirectedFrom: strings.TrimSpace(doc.Find(".mw-redirectedfrom a").First().Text()),
+		raw:            content.Clone(),
+		Content:        clean(content),
+		doc:            doc,
+	}, nil
+}
+
+// isDisambiguation reports whether the page is a disambiguation page, either by
+// its disambiguation notice or the page property MediaWiki marks it with
+func isDisambiguation(doc *goquery.Document) bool {
+	return doc.Find("#disambigbox, .dmbox-disambig, meta[property='mw:PageProp/disambiguation']").Length() > 0
+}
+
+// disambiguationOptions lists the articles linked from the list items of a
+// disambiguation page, using the first link of each item
+func disambiguationOptions(content *goquery.Selection) []Option {
+	var options []Option
+	content.Find("li").Each(func(i int, s *goquery.Selection) {
+		link := s.Find("a[href^='/wiki/']").First()
+		title, ok := link.Attr("title")
+		if !ok || strings.Contains(title, ":") {
+			return
+		}
+		options = append(options, Option{
+			Title:       title,
+			Description: strings.Join(strings.Fields(s.Text()), " "),
+		})
+	})
+	return options
+}
+
+// normalizeTitle turns URL-style titles such as "Sauna_(disambiguation)"
+// into display titles such as "Sauna (disambiguation)"
+func normalizeTitle(title string) string {
+	if unescaped, err := url.PathUnescape(title); err == nil {
+		title = unescaped
+	}
+	return strings.TrimSpace(strings.ReplaceAll(title, "_", " "))
+}
+
+func escapeTitle(title string) string {
+	return url.PathEscape(strings.ReplaceAll(title, " ", "_"))
+}
@@ -0,0 +1,153 @@
+package wiki
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// newTestClient serves the testdata pages from a fake Wikipedia
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	pages := map[string]string{
+		"/wiki/Saunas":                 "sauna.html",
+		"/wiki/Special:Random":         "sauna.html",
+		"/wiki/Sauna_(disambiguation)": "sauna_disambiguation.html",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(WithBaseURL(server.URL))
+}
+
+// parseHTML builds a page from an article body fragment
+func parseHTML(t *testing.T, body string) *Page {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<h1 id="firstHeading">Test</h1><div id="bodyContent">` +
+		`<div id="mw-content-text"><div class="mw-parser-output">` + body + `</div></div></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := parsePage(doc, DefaultLanguage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestFetch(t *testing.T) {
+	page, err := newTestClient(t).Fetch("https://en.m.wikipedia.org/wiki/Saunas", "fi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Title != "Sauna" || page.Lang != "en" || page.RedirectedFrom != "Saunas" {
+		t.Errorf("title/lang/redirect = %q/%q/%q", page.Title, page.Lang, page.RedirectedFrom)
+	}
+	if page.URL != "https://en.wikipedia.org/wiki/Sauna" {
+		t.Errorf("URL = %q", page.URL)
+	}
+
+	text := page.Text()
+	if !strings.HasPrefix(text, "A sauna (/ˈsɔːnə/) is a room") || strings.Contains(text, "[2]") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+	out, err := page.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unwanted := range []string{"<style", "<script", "mw-editsection", "navbox", "reflist"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("HTML should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestFetchDisambiguation(t *testing.T) {
+	_, err := newTestClient(t).Fetch("Sauna_(disambiguation)", "")
+
+	var disambiguation ErrDisambiguation
+	if !errors.As(err, &disambiguation) {
+		t.Fatalf("expected ErrDisambiguation, got %v", err)
+	}
+	want := []Option{
+		{"Sauna (film)", "Sauna (film), a 2008 Finnish horror film"},
+		{"Sauna, Mali", "Sauna, Mali, a village in Mali"},
+		{"Sauna (band)", "Sauna (band), a Finnish rock band"},
+		{"Steam bath", "Steam bath"},
+	}
+	if len(disambiguation.Options) != len(want) {
+		t.Fatalf("options = %+v", disambiguation.Options)
+	}
+	for i, option := range disambiguation.Options {
+		if option != want[i] {
+			t.Errorf("option %d = %+v, want %+v", i, option, want[i])
+		}
+	}
+}
+
+func TestFetchNotFound(t *testing.T) {
+	_, err := newTestClient(t).Fetch("No such article", "en")
+	if !errors.As(err, &ErrNotFound{}) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	page, err := newTestClient(t).Random("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Title != "Sauna" || page.Lang != DefaultLanguage {
+		t.Errorf("title/lang = %q/%q", page.Title, page.Lang)
+	}
+	for _, lang := range []string{"evil.example.com/", "EN", "en wiki"} {
+		if _, err := newTestClient(t).Random(lang); err == nil || !strings.Contains(err.Error(), "invalid Wikipedia language") {
+			t.Errorf("Random(%q) = %v, want an invalid language error", lang, err)
+		}
+	}
+}
+
+func TestParseArticle(t *testing.T) {
+	tests := []struct {
+		input, lang     string
+		title, wantLang string
+	}{
+		{"Sauna", "", "Sauna", "en"},
+		{"Helsinki_Central_Station", "fi", "Helsinki Central Station", "fi"},
+		{"https://fi.wikipedia.org/wiki/Sisu", "en", "Sisu", "fi"},
+		{"https://sv.m.wikipedia.org/wiki/Bastu", "", "Bastu", "sv"},
+		{"https://en.wikipedia.org/wiki/Sauna_%28disambiguation%29", "", "Sauna (disambiguation)", "en"},
+		{"https://de.wikipedia.org/w/index.php?title=Sauna&action=history", "", "Sauna", "de"},
+	}
+	for _, tt := range tests {
+		title, lang, err := ParseArticle(tt.input, tt.lang)
+		if err != nil || title != tt.title || lang != tt.wantLang {
+			t.Errorf("ParseArticle(%q, %q) = %q, %q, %v; want %q, %q", tt.input, tt.lang, title, lang, err, tt.title, tt.wantLang)
+		}
+	}
+
+	for _, input := range []string{"", "https://example.com/wiki/Sauna", "https://en.wikipedia.org/"} {
+		if _, _, err := ParseArticle(input, ""); err == nil {
+			t.Errorf("ParseArticle(%q) should fail", input)
+		}
+	}
+	if _, _, err := ParseArticle("Sauna", "../x"); err == nil {
+		t.Error("expected an invalid language to be rejected")
+	}
+}
@@ -0,0 +1,89 @@
+package words
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// ankiHeaders tell Anki 2.1.55 and later how to import the deck, so that
+// it needs no setting up in the import dialog
+var ankiHeaders = []string{
+	"#separator:Comma",
+	"#html:true",
+	"#columns:Front,Back,Tags",
+	"#tags column:3",
+}
+
+// WriteAnki writes unknown words as a CSV deck that Anki can import. The
+// front of each card is the word; the back is the example sentence with the
+// word in bold and, for timed documents, a link to the moment it is said.
+func WriteAnki(w io.Writer, doc *Document, unknown []UnknownWord) error {
+	for _, header := range ankiHeaders {
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+	}
+
+	tags := "sanoja " + doc.Source
+	records := make([][]string, len(unknown))
+	for i, word := range unknown {
+		back := highlight(word.Example, word)
+		if link := ankiLink(doc, word); link != "" {
+			back += "<br>" + link
+		}
+		records[i] = []string{html.EscapeString(word.Word), back, tags}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.WriteAll(records)
+	return cw.Error()
+}
+
+// ankiLink links to where a word was found: the moment in a YouTube video,
+// or the document's location
+func ankiLink(doc *Document, word UnknownWord) string {
+	switch {
+	case doc.Source == SourceYouTube && word.Start != nil:
+		seconds := int(*word.Start)
+		href := fmt.Sprintf("%s&t=%ds", doc.Location, seconds)
+		return fmt.Sprintf(`<a href="%s">%s %d:%02d</a>`, html.EscapeString(href), html.EscapeString(doc.Title), seconds/60, seconds%60)
+	case strings.HasPrefix(doc.Location, "http"):
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(doc.Location), html.EscapeString(doc.Title))
+	}
+	return ""
+}
+
+// highlight escapes text as HTML and puts the forms of a word in bold
+func highlight(text string, word UnknownWord) string {
+	forms := map[string]bool{word.Word: true}
+	for _, form := range word.Forms {
+		forms[form] = true
+	}
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && (isWordRune(runes[j]) || isJoiner(runes[j]) && j > i) {
+			j++
+		}
+		if j == i {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		chunk := string(runes[i:j])
+		tokens := Tokenize(chunk)
+		if len(tokens) == 1 && forms[tokens[0]] {
+			b.WriteString("<b>" + html.EscapeString(chunk) + "</b>")
+		} else {
+			b.WriteString(html.EscapeString(chunk))
+		}
+		i = j
+	}
+	return b.String()
+}
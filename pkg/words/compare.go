@@ -0,0 +1,164 @@
+package words
+
+import (
+	"math"
+	"sort"
+)
+
+// Comparison describes how the vocabularies of several documents differ
+type Comparison struct {
+	Documents []ComparedDocument `json:"documents"`
+	// Shared are the words used in every document, most frequent first
+	Shared []Count `json:"shared"`
+	// SharedTotal is the number of words used in every document
+	SharedTotal int `json:"sharedTotal"`
+	// Pairs compare every two documents
+	Pairs []Similarity `json:"pairs"`
+}
+
+// ComparedDocument is the vocabulary of one document in a comparison
+type ComparedDocument struct {
+	Title    string `json:"title"`
+	Source   string `json:"source"`
+	Location string `json:"location,omitempty"`
+	Tokens   int    `json:"tokens"`
+	Unique   int    `json:"unique"`
+	// Top are the most frequent words
+	Top []Count `json:"top"`
+	// Distinctive are the words that set the document apart from the others,
+	// highest TF-IDF first
+	Distinctive []WeightedTerm `json:"distinctive"`
+}
+
+// WeightedTerm is a term with its count and TF-IDF weight in a document
+type WeightedTerm struct {
+	Term  string  `json:"term"`
+	Count int     `json:"count"`
+	TFIDF float64 `json:"tfidf"`
+}
+
+// Similarity compares the vocabularies of two documents, given by their
+// index in Comparison.Documents
+type Similarity struct {
+	A int `json:"a"`
+	B int `json:"b"`
+	// Jaccard is the number of shared words over the number of words in either
+	Jaccard float64 `json:"jaccard"`
+	// Cosine is the cosine similarity of the word counts
+	Cosine float64 `json:"cosine"`
+	// Shared is the number of words used in both
+	Shared int `json:"shared"`
+}
+
+// Compare compares the vocabularies of documents, keeping the top most
+// frequent, distinctive and shared words of each. A top of 0 keeps all.
+//
+// A word's TF-IDF weight in a document is its frequency there times the log
+// of the number of documents over the number of documents using it, so words
+// found in every document weigh nothing.
+func Compare(docs []*Document, top int) *Comparison {
+	counts := make([]map[string]int, len(docs))
+	totals := make([]int, len(docs))
+	df := make(map[string]int)
+	c := &Comparison{Documents: []ComparedDocument{}, Shared: []Count{}, Pairs: []Similarity{}}
+
+	for i, doc := range docs {
+		tokens := doc.Tokens()
+		frequencies := Frequencies(tokens)
+		counts[i] = make(map[string]int, len(frequencies))
+		for _, f := range frequencies {
+			counts[i][f.Term] = f.Count
+			df[f.Term]++
+		}
+		totals[i] = len(tokens)
+
+		c.Documents = append(c.Documents, ComparedDocument{
+			Title:    doc.Title,
+			Source:   doc.Source,
+			Location: doc.Location,
+			Tokens:   len(tokens),
+			Unique:   len(frequencies),
+			Top:      limit(frequencies, top),
+		})
+	}
+
+	n := float64(len(docs))
+	for i := range c.Documents {
+		distinctive := []WeightedTerm{}
+		for term, count := range counts[i] {
+			idf := math.Log(n / float64(df[term]))
+			if idf <= 0 {
+				continue
+			}
+			tf := float64(count) / float64(totals[i])
+			distinctive = append(distinctive, WeightedTerm{Term: term, Count: count, TFIDF: round(tf*idf, 4)})
+		}
+		sort.Slice(distinctive, func(a, b int) bool {
+			x, y := distinctive[a], distinctive[b]
+			if x.TFIDF != y.TFIDF {
+				return x.TFIDF > y.TFIDF
+			}
+			return x.Term < y.Term
+		})
+		if top > 0 && len(distinctive) > top {
+			distinctive = distinctive[:top]
+		}
+		c.Documents[i].Distinctive = distinctive
+	}
+
+	var shared []Count
+	for term, n := range df {
+		if n < len(docs) {
+			continue
+		}
+		total := 0
+		for _, doc := range counts {
+			total += doc[term]
+		}
+		shared = append(shared, Count{Term: term, Count: total})
+	}
+	sortCounts(shared)
+	c.SharedTotal = len(shared)
+	c.Shared = limit(shared, top)
+
+	for a := 0; a < len(docs); a++ {
+		for b := a + 1; b < len(docs); b++ {
+			c.Pairs = append(c.Pairs, similarity(a, b, counts[a], counts[b]))
+		}
+	}
+	return c
+}
+
+func similarity(a, b int, x, y map[string]int) Similarity {
+	s := Similarity{A: a, B: b}
+	var dot, normX, normY float64
+	for term, cx := range x {
+		normX += float64(cx * cx)
+		if cy, ok := y[term]; ok {
+			s.Shared++
+			dot += float64(cx * cy)
+		}
+	}
+	for _, cy := range y {
+		normY += float64(cy * cy)
+	}
+
+	if union := len(x) + len(y) - s.Shared; union > 0 {
+		s.Jaccard = round(float64(s.Shared)/float64(union), 3)
+	}
+	if normX > 0 && normY > 0 {
+		s.Cosine = round(dot/math.Sqrt(normX*normY), 3)
+	}
+	return s
+}
+
+// limit keeps the first top counts, or all of them if top is 0
+func limit(counts []Count, top int) []Count {
+	if top > 0 && len(counts) > top {
+		counts = counts[:top]
+	}
+	if counts == nil {
+		counts = []Count{}
+	}
+	return counts
+}
@@ -0,0 +1,41 @@
+package words
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	docs := []*Document{
+		{Title: "a", Segments: paragraphs("Sauna on kuuma ja sauna on hyvä.")},
+		{Title: "b", Segments: paragraphs("Järvi on kylmä ja sauna on lämmin.")},
+		{Title: "c", Segments: paragraphs("Something else entirely.")},
+	}
+
+	c := Compare(docs[:2], 0)
+	if want := []Count{{"on", 4}, {"sauna", 3}, {"ja", 2}}; !reflect.DeepEqual(c.Shared, want) || c.SharedTotal != 3 {
+		t.Errorf("shared = %v (%d), want %v", c.Shared, c.SharedTotal, want)
+	}
+	if want := []Similarity{{A: 0, B: 1, Jaccard: 0.375, Cosine: 0.704, Shared: 3}}; !reflect.DeepEqual(c.Pairs, want) {
+		t.Errorf("pairs = %+v, want %+v", c.Pairs, want)
+	}
+	// Words used in both documents weigh nothing
+	want := []WeightedTerm{{"hyvä", 1, 0.099}, {"kuuma", 1, 0.099}}
+	if got := c.Documents[0].Distinctive; !reflect.DeepEqual(got, want) {
+		t.Errorf("distinctive terms of a = %+v, want %+v", got, want)
+	}
+	if d := c.Documents[1]; d.Tokens != 7 || d.Unique != 6 || len(d.Distinctive) != 3 {
+		t.Errorf("unexpected document b: %+v", d)
+	}
+
+	c = Compare(docs, 1)
+	if len(c.Pairs) != 3 || c.SharedTotal != 0 || len(c.Shared) != 0 {
+		t.Errorf("unexpected comparison of three documents: %+v", c)
+	}
+	if p := c.Pairs[1]; p.A != 0 || p.B != 2 || p.Jaccard != 0 || p.Cosine != 0 {
+		t.Errorf("unrelated documents should not be similar: %+v", p)
+	}
+	if len(c.Documents[0].Top) != 1 || len(c.Documents[0].Distinctive) != 1 {
+		t.Errorf("top should limit the terms: %+v", c.Documents[0])
+	}
+}
@@ -0,0 +1,105 @@
+// Package words analyses the vocabulary of text from any source: YouTube
+// transcripts, Wikipedia articles, files or standard input. Each source
+// produces a Document, which the tokenizer and the analyses work on.
+package words
+
+import (
+	"strings"
+
+	"github.com/mjlefevre/sanoja/pkg/langid"
+)
+
+// Source kinds
+const (
+	SourceYouTube   = "youtube"
+	SourceWikipedia = "wikipedia"
+	SourceFile      = "file"
+	SourceStdin     = "stdin"
+)
+
+// Segment is a piece of a document: a transcript entry or a paragraph
+type Segment struct {
+	Text string `json:"text"`
+	// Start and Duration are in seconds and only set for timed documents
+	Start    float64 `json:"start,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// Document is text to analyse, split into segments
+type Document struct {
+	Title string `json:"title"`
+	// Source is the kind of source the document came from, e.g. SourceYouTube
+	Source string `json:"source"`
+	// Location is the URL or path the document was read from
+	Location string `json:"location,omitempty"`
+	// Lang is the declared language of the document, if known
+	Lang string `json:"lang,omitempty"`
+	// AnalysisLang, if set, is the language to analyse the document in
+	// regardless of the declared and detected ones, e.g. one chosen by the user
+	AnalysisLang string `json:"-"`
+	// Timed reports whether the segments have timestamps
+	Timed    bool      `json:"timed"`
+	Segments []Segment `json:"segments"`
+
+	detected *langid.Result
+}
+
+// DetectLanguage identifies the language of the text. The result is
+// computed once and kept with the document.
+func (d *Document) DetectLanguage() langid.Result {
+	if d.detected == nil {
+		result := langid.Detect(d.Text())
+		d.detected = &result
+	}
+	return *d.detected
+}
+
+// Language returns the language to analyse the document in: AnalysisLang if
+// set, then the detected language if the detection is reliable, since
+// declared languages are sometimes wrong, then the declared language, then
+// fallback. A declared language the detection does not support is kept, as
+// the detection cannot tell it apart from a supported relative.
+func (d *Document) Language(fallback string) string {
+	if d.AnalysisLang != "" {
+		return d.AnalysisLang
+	}
+	if d.Lang != "" && !langid.Supported(d.Lang) {
+		return d.Lang
+	}
+	if detected := d.DetectLanguage(); detected.Reliable {
+		return detected.Language
+	}
+	if d.Lang != "" {
+		return d.Lang
+	}
+	return fallback
+}
+
+// Text returns the text of all segments separated by newlines
+func (d *Document) Text() string {
+	texts := make([]string, len(d.Segments))
+	for i, s := range d.Segments {
+		texts[i] = s.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Tokens returns the words of the whole document
+func (d *Document) Tokens() []string {
+	var tokens []string
+	for _, s := range d.Segments {
+		tokens = append(tokens, Tokenize(s.Text)...)
+	}
+	return tokens
+}
+
+// paragraphs splits text into segments at blank lines
+func paragraphs(text string) []Segment {
+	var segments []Segment
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			segments = append(segments, Segment{Text: p})
+		}
+	}
+	return segments
+}
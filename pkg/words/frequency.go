@@ -0,0 +1,75 @@
+package words
+
+import (
+	"sort"
+	"strings"
+)
+
+// Count is a term with the number of times it occurs
+type Count struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// Frequencies counts the tokens, most frequent first and alphabetically among equals
+func Frequencies(tokens []string) []Count {
+	return count(tokens)
+}
+
+// NGrams counts the sequences of n consecutive tokens, joined by spaces,
+// most frequent first. NGrams(tokens, 1) is the same as Frequencies(tokens).
+func NGrams(tokens []string, n int) []Count {
+	if n < 1 || len(tokens) < n {
+		return nil
+	}
+	grams := make([]string, 0, len(tokens)-n+1)
+	for i := 0; i+n <= len(tokens); i++ {
+		grams = append(grams, strings.Join(tokens[i:i+n], " "))
+	}
+	return count(grams)
+}
+
+func count(terms []string) []Count {
+	counts := make(map[string]int)
+	for _, t := range terms {
+		counts[t]++
+	}
+
+	result := make([]Count, 0, len(counts))
+	for term, n := range counts {
+		result = append(result, Count{Term: term, Count: n})
+	}
+	sortCounts(result)
+	return result
+}
+
+// sortCounts sorts counts most frequent first and alphabetically among equals
+func sortCounts(counts []Count) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Term < counts[j].Term
+	})
+}
+
+// Analysis summarises the vocabulary of a document
+type Analysis struct {
+	Tokens int `json:"tokens"`
+	Unique int `json:"unique"`
+	// N is the n-gram size of Terms, 1 for single words
+	N     int     `json:"n"`
+	Terms []Count `json:"terms"`
+}
+
+// Analyze counts the n-grams of a document, keeping the top most frequent.
+// A top of 0 keeps all terms.
+func Analyze(doc *Document, n, top int) *Analysis {
+	tokens := doc.Tokens()
+	return &Analysis{
+		Tokens: len(tokens),
+		Unique: len(Frequencies(tokens)),
+		N:      n,
+		Terms:  limit(NGrams(tokens, n), top),
+	}
+}
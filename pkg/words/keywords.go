@@ -0,0 +1,147 @@
+package words
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxPhraseWords is the length of the longest key phrase. Longer runs of
+// words without stop words are rarely topics and are not candidates.
+const maxPhraseWords = 4
+
+// Keyword is a key phrase of a document
+type Keyword struct {
+	Phrase string  `json:"phrase"`
+	Score  float64 `json:"score"`
+	// Count is the number of times the phrase occurs
+	Count int `json:"count"`
+	// First and Starts are the times of the first and of all occurrences in
+	// seconds, for timed documents
+	First  *float64  `json:"first,omitempty"`
+	Starts []float64 `json:"starts,omitempty"`
+}
+
+// phrase is an occurrence of a candidate key phrase
+type phrase struct {
+	words   []string
+	segment int
+}
+
+// ExtractKeywords finds the key phrases of a document with RAKE (Rose et
+// al., "Automatic keyword extraction from individual documents", 2010) and
+// returns the top highest scoring. A top of 0 keeps all.
+//
+// Candidate phrases are the runs of words between stop words and
+// punctuation. Each word scores its degree, the total length of the
+// candidates it appears in, over its frequency, which favours words that
+// make up longer phrases; a phrase scores the sum of its words. Languages
+// without a stop word list have no phrases, so their words are ranked by
+// frequency alone. The document's detected or declared language takes precedence.
+func ExtractKeywords(doc *Document, lang string, top int) []Keyword {
+	lang = doc.Language(lang)
+	candidates := candidatePhrases(doc, StopWords(lang))
+
+	frequency := make(map[string]int)
+	degree := make(map[string]int)
+	for _, p := range candidates {
+		for _, w := range p.words {
+			frequency[w]++
+			degree[w] += len(p.words)
+		}
+	}
+
+	byPhrase := make(map[string]*Keyword)
+	var order []string
+	for _, p := range candidates {
+		key := strings.Join(p.words, " ")
+		k, ok := byPhrase[key]
+		if !ok {
+			k = &Keyword{Phrase: key}
+			for _, w := range p.words {
+				k.Score += float64(degree[w]) / float64(frequency[w])
+			}
+			k.Score = round(k.Score, 2)
+			byPhrase[key] = k
+			order = append(order, key)
+		}
+		k.Count++
+		if doc.Timed {
+			start := doc.Segments[p.segment].Start
+			if k.First == nil {
+				k.First = &start
+			}
+			k.Starts = append(k.Starts, start)
+		}
+	}
+
+	keywords := make([]Keyword, 0, len(order))
+	for _, key := range order {
+		keywords = append(keywords, *byPhrase[key])
+	}
+	sort.SliceStable(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Count > keywords[j].Count
+	})
+	if top > 0 && len(keywords) > top {
+		keywords = keywords[:top]
+	}
+	return keywords
+}
+
+// candidatePhrases splits a document into runs of words delimited by stop
+// words and punctuation. Phrases of timed documents run across captions,
+// while paragraphs of other documents always end a phrase. Without stop
+// words every word is a phrase of its own.
+func candidatePhrases(doc *Document, stop map[string]bool) []phrase {
+	var phrases []phrase
+	var current phrase
+
+	flush := func() {
+		if len(current.words) > 0 && len(current.words) <= maxPhraseWords {
+			phrases = append(phrases, current)
+		}
+		current = phrase{}
+	}
+	add := func(word string, segment int) {
+		if stop[word] || len([]rune(word)) < 2 {
+			flush()
+			return
+		}
+		if len(current.words) == 0 {
+			current.segment = segment
+		}
+		current.words = append(current.words, word)
+		if stop == nil {
+			flush()
+		}
+	}
+
+	for i, segment := range doc.Segments {
+		runes := []rune(segment.Text)
+		for start := 0; start < len(runes); {
+			end := start
+			for end < len(runes) && (isWordRune(runes[end]) || isJoiner(runes[end]) && end > start) {
+				end++
+			}
+			if end == start {
+				if !unicode.IsSpace(runes[start]) {
+					flush()
+				}
+				start++
+				continue
+			}
+			for _, token := range Tokenize(string(runes[start:end])) {
+				add(token, i)
+			}
+			start = end
+		}
+		if !doc.Timed {
+			flush()
+		}
+	}
+	flush()
+	return phrases
+}
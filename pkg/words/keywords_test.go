@@ -0,0 +1,60 @@
+package words
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractKeywords(t *testing.T) {
+	doc := &Document{
+		Timed: true,
+		Segments: []Segment{
+			{Text: "Linear programming is a method for", Start: 0},
+			{Text: "optimization. Linear programming, again!", Start: 4},
+			{Text: "The method is simple.", Start: 9},
+		},
+	}
+
+	keywords := ExtractKeywords(doc, "en", 0)
+	var phrases []string
+	for _, k := range keywords {
+		phrases = append(phrases, k.Phrase)
+	}
+	// "again" is a stop word, and the comma ends a phrase
+	if want := []string{"linear programming", "method", "optimization", "simple"}; !reflect.DeepEqual(phrases, want) {
+		t.Fatalf("phrases = %v, want %v", phrases, want)
+	}
+
+	lp := keywords[0]
+	if lp.Score != 4 || lp.Count != 2 || *lp.First != 0 || !reflect.DeepEqual(lp.Starts, []float64{0, 4}) {
+		t.Errorf("unexpected keyword: %+v", lp)
+	}
+	if method := keywords[1]; method.Score != 1 || method.Count != 2 || *method.First != 0 {
+		t.Errorf("unexpected keyword: %+v", method)
+	}
+
+	if got := ExtractKeywords(doc, "en", 1); len(got) != 1 {
+		t.Errorf("top should limit the keywords, got %v", got)
+	}
+
+	// Without stop words every word stands alone, ranked by frequency
+	untimed := &Document{Lang: "xx", Segments: paragraphs("foo bar foo")}
+	want := []Keyword{{Phrase: "foo", Score: 1, Count: 2}, {Phrase: "bar", Score: 1, Count: 1}}
+	if got := ExtractKeywords(untimed, "en", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("keywords = %+v, want %+v", got, want)
+	}
+}
+
+func TestStopWords(t *testing.T) {
+	for _, lang := range []string{"en", "fi", "sv", "de", "fr", "es"} {
+		if len(StopWords(lang)) < 100 {
+			t.Errorf("expected a stop word list for %s", lang)
+		}
+	}
+	if en := StopWords("en-GB"); !en["the"] || !en["actually"] || en["sauna"] {
+		t.Error("unexpected English stop words")
+	}
+	if StopWords("xx") != nil {
+		t.Error("expected no stop words for an unknown language")
+	}
+}
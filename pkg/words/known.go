@@ -0,0 +1,151 @@
+package words
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KnownWords maps languages to the words a learner already knows
+type KnownWords map[string][]string
+
+// LoadKnownWords reads known words from a YAML file of the form
+//
+//	en: [apple, house, run]
+//	fi: [sauna, löyly]
+//
+// A missing file yields no known words.
+func LoadKnownWords(path string) (KnownWords, error) {
+	known := make(KnownWords)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading known words: %v", err)
+	}
+
+	if err := yaml.Unmarshal(data, &known); err != nil {
+		return nil, fmt.Errorf("error parsing known words file %s: %v", path, err)
+	}
+	if known == nil {
+		known = make(KnownWords)
+	}
+	return known, nil
+}
+
+// Save writes the known words to a YAML file
+func (k KnownWords) Save(path string) error {
+	data, err := yaml.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("error encoding known words: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating known words directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing known words: %v", err)
+	}
+	return nil
+}
+
+// Add adds words to a language, keeping the list sorted, and returns the
+// number of words that were new
+func (k KnownWords) Add(lang string, words ...string) int {
+	lang = baseLanguage(lang)
+	set := make(map[string]bool)
+	for _, w := range k[lang] {
+		set[w] = true
+	}
+
+	added := 0
+	for _, w := range words {
+		w = normalizeWord(w)
+		if w == "" || set[w] {
+			continue
+		}
+		set[w] = true
+		k[lang] = append(k[lang], w)
+		added++
+	}
+	sort.Strings(k[lang])
+	return added
+}
+
+// Remove removes words from a language and returns the number removed
+func (k KnownWords) Remove(lang string, words ...string) int {
+	lang = baseLanguage(lang)
+	remove := make(map[string]bool)
+	for _, w := range words {
+		remove[normalizeWord(w)] = true
+	}
+
+	var kept []string
+	for _, w := range k[lang] {
+		if !remove[w] {
+			kept = append(kept, w)
+		}
+	}
+	removed := len(k[lang]) - len(kept)
+	if len(kept) == 0 {
+		delete(k, lang)
+	} else {
+		k[lang] = kept
+	}
+	return removed
+}
+
+// Words returns the known words of a language
+func (k KnownWords) Words(lang string) []string {
+	return k[baseLanguage(lang)]
+}
+
+// Stems returns the stems of the known words of a language, so that knowing
+// "run" also covers "runs" and "running"
+func (k KnownWords) Stems(lang string) map[string]bool {
+	stems := make(map[string]bool)
+	for _, w := range k.Words(lang) {
+		stems[Stem(w, lang)] = true
+	}
+	return stems
+}
+
+// ReadWordList reads words from a list with one word per line. Only the
+// first field of CSV or tab-separated lines is used, so exported flashcard
+// decks can be imported as is. Blank lines and lines starting with # are skipped.
+func ReadWordList(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.IndexAny(line, ",;\t"); i >= 0 {
+			line = line[:i]
+		}
+		if w := normalizeWord(strings.Trim(line, `"`)); w != "" {
+			words = append(words, w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading word list: %v", err)
+	}
+	return words, nil
+}
+
+// normalizeWord lower-cases a word the way Tokenize does
+func normalizeWord(word string) string {
+	tokens := Tokenize(word)
+	if len(tokens) != 1 {
+		return strings.ToLower(strings.TrimSpace(word))
+	}
+	return tokens[0]
+}
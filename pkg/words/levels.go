@@ -0,0 +1,159 @@
+package words
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+//go:embed wordlists/*.txt
+var wordlists embed.FS
+
+// Levels are the CEFR-style difficulty levels, from easiest to hardest.
+// Words missing from a language's word list are at the last level.
+var Levels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// comprehensionCoverage is the share of words a reader must know to follow a
+// text without help, the usual threshold in vocabulary research
+const comprehensionCoverage = 0.95
+
+// Difficulty estimates the level a learner needs to understand a text
+type Difficulty struct {
+	// Level is the lowest level whose vocabulary covers enough of the text
+	Level string `json:"level"`
+	// Coverage is the percentage of words known at each level, cumulatively
+	Coverage []LevelCoverage `json:"coverage"`
+	// Names is the number of words left out as names
+	Names int `json:"names,omitempty"`
+}
+
+// LevelCoverage is the percentage of a text's words known at a level
+type LevelCoverage struct {
+	Level   string  `json:"level"`
+	Percent float64 `json:"percent"`
+}
+
+// vocabulary is a word list: the index in Levels of each listed word and,
+// so that inflected forms are found too, of each of their stems
+type vocabulary struct {
+	words map[string]int
+	stems map[string]int
+}
+
+var (
+	vocabulariesMu sync.Mutex
+	vocabularies   = make(map[string]*vocabulary)
+)
+
+// loadVocabulary reads the bundled word list of a language, if there is one
+func loadVocabulary(lang string) (*vocabulary, bool) {
+	lang = baseLanguage(lang)
+
+	vocabulariesMu.Lock()
+	defer vocabulariesMu.Unlock()
+	if v, ok := vocabularies[lang]; ok {
+		return v, v != nil
+	}
+
+	f, err := wordlists.Open("wordlists/" + lang + ".txt")
+	if err != nil {
+		vocabularies[lang] = nil
+		return nil, false
+	}
+	defer f.Close()
+
+	v := &vocabulary{words: make(map[string]int), stems: make(map[string]int)}
+	level := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			level = levelIndex(strings.Trim(line, "[]"))
+		default:
+			// Levels are listed from easiest, so the first one found is kept
+			if _, ok := v.words[line]; !ok {
+				v.words[line] = level
+			}
+			if stem := Stem(line, lang); stem != "" {
+				if _, ok := v.stems[stem]; !ok {
+					v.stems[stem] = level
+				}
+			}
+		}
+	}
+	vocabularies[lang] = v
+	return v, true
+}
+
+// level returns the level of a token. Inflected forms are matched on their
+// stem like known words are, so "studies" counts as "study" in English.
+func (v *vocabulary) level(token, lang string) int {
+	if level, ok := v.words[token]; ok {
+		return level
+	}
+	if level, ok := v.stems[Stem(token, lang)]; ok {
+		return level
+	}
+	return len(Levels) - 1
+}
+
+// EstimateDifficulty estimates the level of a document's vocabulary in the
+// given language. It returns nil for languages without a bundled word list.
+// Unlisted words that are names by the rule of nameTokens are left out,
+// since knowing them says nothing about the reader's level.
+func EstimateDifficulty(doc *Document, lang string) *Difficulty {
+	v, ok := loadVocabulary(lang)
+	if !ok {
+		return nil
+	}
+
+	_, occurrences := scanSentences(doc)
+	names := nameTokens(occurrences)
+	counts := make([]int, len(Levels))
+	d := &Difficulty{Level: Levels[len(Levels)-1]}
+	total := 0
+	for _, token := range doc.Tokens() {
+		level := v.level(token, lang)
+		if level == len(Levels)-1 && names[token] {
+			d.Names++
+			continue
+		}
+		counts[level]++
+		total++
+	}
+	if total == 0 {
+		return nil
+	}
+
+	known := 0
+	for i, level := range Levels {
+		known += counts[i]
+		share := float64(known) / float64(total)
+		d.Coverage = append(d.Coverage, LevelCoverage{Level: level, Percent: round(share*100, 1)})
+		if share >= comprehensionCoverage && d.Level == Levels[len(Levels)-1] {
+			d.Level = level
+		}
+	}
+	return d
+}
+
+func levelIndex(level string) int {
+	for i, l := range Levels {
+		if strings.EqualFold(l, level) {
+			return i
+		}
+	}
+	return len(Levels) - 1
+}
+
+// baseLanguage strips the region from a language code, e.g. en-US becomes en
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
@@ -0,0 +1,112 @@
+package words
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSentenceWords is the length at which a sentence of a timed document is
+// cut at the end of a caption, so that captions without punctuation still
+// give sentences of a usable length
+const maxSentenceWords = 40
+
+// Sentence is a sentence of a document
+type Sentence struct {
+	Text  string `json:"text"`
+	Words int    `json:"words"`
+	// Segment is the index of the segment the sentence starts in
+	Segment int `json:"segment"`
+	// Start is the time the sentence starts in seconds, for timed documents
+	Start *float64 `json:"start,omitempty"`
+}
+
+// Sentences splits a document into sentences. The sentences of timed
+// documents run across segments, since captions break lines mid-sentence,
+// but captions without punctuation are cut into pieces of about
+// maxSentenceWords words at caption boundaries.
+func (d *Document) Sentences() []Sentence {
+	sentences, _ := scanSentences(d)
+	return sentences
+}
+
+// scanSentences splits a document into sentences and records where each of
+// its tokens occurs
+func scanSentences(doc *Document) ([]Sentence, []occurrence) {
+	var sentences []Sentence
+	var occurrences []occurrence
+	var text []string
+	words, start := 0, 0
+
+	flush := func() {
+		if words > 0 {
+			s := Sentence{Text: strings.Join(text, " "), Words: words, Segment: start}
+			if doc.Timed {
+				t := doc.Segments[start].Start
+				s.Start = &t
+			}
+			sentences = append(sentences, s)
+		}
+		text, words = nil, 0
+	}
+
+	for i, segment := range doc.Segments {
+		pieces := sentenceEnd.Split(segment.Text, -1)
+		ends := sentenceEnd.FindAllString(segment.Text, -1)
+		for j, piece := range pieces {
+			if words == 0 {
+				start = i
+			}
+			for _, word := range strings.FieldsFunc(piece, func(r rune) bool { return !isWordRune(r) && !isJoiner(r) }) {
+				r, _ := utf8.DecodeRuneInString(strings.TrimLeft(word, "'’-‐"))
+				for _, token := range Tokenize(word) {
+					capitalized := unicode.IsUpper(r)
+					occurrences = append(occurrences, occurrence{
+						token:       token,
+						sentence:    len(sentences),
+						segment:     i,
+						capitalized: capitalized,
+						name:        capitalized && words > 0,
+					})
+					words++
+				}
+			}
+
+			if piece = strings.TrimSpace(piece); piece != "" {
+				if j < len(ends) {
+					piece += strings.TrimSpace(ends[j])
+				}
+				text = append(text, piece)
+			}
+			if j < len(ends) {
+				flush()
+			}
+		}
+		if !doc.Timed || words >= maxSentenceWords {
+			flush()
+		}
+	}
+	flush()
+
+	return sentences, occurrences
+}
+
+// nameTokens returns the tokens taken to be names: words that are only ever
+// capitalized and are capitalized within a sentence at least once, so that
+// an ordinary word starting a sentence is not mistaken for one
+func nameTokens(occurrences []occurrence) map[string]bool {
+	names := make(map[string]bool)
+	lower := make(map[string]bool)
+	for _, o := range occurrences {
+		if !o.capitalized {
+			lower[o.token] = true
+		}
+		if o.name {
+			names[o.token] = true
+		}
+	}
+	for token := range lower {
+		delete(names, token)
+	}
+	return names
+}
@@ -0,0 +1,131 @@
+package words
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mjlefevre/sanoja/pkg/transcript"
+	"github.com/mjlefevre/sanoja/pkg/wiki"
+)
+
+// Source loads a Document
+type Source interface {
+	Load() (*Document, error)
+}
+
+// TranscriptSource loads the transcript of a YouTube video as a timed document
+type TranscriptSource struct {
+	Client *transcript.Client
+	// Video is a video ID or URL
+	Video string
+	// Languages are the preferred transcript languages, in order of preference
+	Languages []string
+}
+
+// Load implements Source
+func (s TranscriptSource) Load() (*Document, error) {
+	videoID := transcript.ExtractVideoID(s.Video)
+	if videoID == "" {
+		return nil, fmt.Errorf("invalid YouTube URL or Video ID: %s", s.Video)
+	}
+
+	languages := s.Languages
+	if len(languages) == 0 {
+		languages = []string{"en"}
+	}
+	entries, track, err := s.Client.GetTranscriptTrack(videoID, languages)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching transcript: %v", err)
+	}
+
+	doc := NewTranscriptDocument(videoID, entries)
+	doc.Lang = track.LanguageCode
+	return doc, nil
+}
+
+// NewTranscriptDocument creates a timed document from transcript entries
+// that were already fetched. Transcripts do not include the video title, so
+// the document is titled "video <id>".
+func NewTranscriptDocument(videoID string, entries []transcript.TranscriptEntry) *Document {
+	doc := &Document{
+		Title:    "video " + videoID,
+		Source:   SourceYouTube,
+		Location: "https://www.youtube.com/watch?v=" + videoID,
+		Timed:    true,
+	}
+	for _, e := range entries {
+		doc.Segments = append(doc.Segments, Segment{Text: e.Text, Start: e.Start, Duration: e.Duration})
+	}
+	return doc
+}
+
+// WikiSource loads a Wikipedia article, or a random one if Title is empty.
+// Each paragraph of the article becomes a segment.
+type WikiSource struct {
+	Client *wiki.Client
+	// Title is an article title or URL
+	Title string
+	Lang  string
+}
+
+// Load implements Source
+func (s WikiSource) Load() (*Document, error) {
+	var page *wiki.Page
+	var err error
+	if s.Title == "" {
+		page, err = s.Client.Random(s.Lang)
+	} else {
+		page, err = s.Client.Fetch(s.Title, s.Lang)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Title:    page.Title,
+		Source:   SourceWikipedia,
+		Location: page.URL,
+		Lang:     page.Lang,
+		Segments: paragraphs(page.Text()),
+	}, nil
+}
+
+// FileSource loads a local text file, one segment per paragraph
+type FileSource struct {
+	Path string
+}
+
+// Load implements Source
+func (s FileSource) Load() (*Document, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	return &Document{
+		Title:    filepath.Base(s.Path),
+		Source:   SourceFile,
+		Location: s.Path,
+		Segments: paragraphs(string(data)),
+	}, nil
+}
+
+// ReaderSource loads text from a reader such as standard input, one segment per paragraph
+type ReaderSource struct {
+	Name   string
+	Reader io.Reader
+}
+
+// Load implements Source
+func (s ReaderSource) Load() (*Document, error) {
+	data, err := io.ReadAll(s.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", s.Name, err)
+	}
+	return &Document{
+		Title:    s.Name,
+		Source:   SourceStdin,
+		Segments: paragraphs(string(data)),
+	}, nil
+}
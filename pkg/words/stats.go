@@ -0,0 +1,275 @@
+package words
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// sentenceEnd matches the punctuation ending a sentence, with any closing
+// quotes or brackets, when followed by space or the end of the text
+var sentenceEnd = regexp.MustCompile(`[.!?…]+["'”’)\]]*(\s+|$)`)
+
+// Stats describes how difficult a document is to read or follow
+type Stats struct {
+	Words     int `json:"words"`
+	Unique    int `json:"unique"`
+	Sentences int `json:"sentences"`
+	// SentencesEstimated is set when the text has no sentence punctuation,
+	// as with automatic captions, and each segment was counted as a sentence
+	SentencesEstimated bool `json:"sentencesEstimated,omitempty"`
+	Syllables          int  `json:"syllables"`
+
+	// TypeTokenRatio is the number of unique words per word
+	TypeTokenRatio float64 `json:"typeTokenRatio"`
+	// LexicalDensity is the share of content words, as opposed to function
+	// words like articles and prepositions. It is only computed for
+	// languages with a list of function words.
+	LexicalDensity    float64 `json:"lexicalDensity,omitempty"`
+	AvgWordLength     float64 `json:"avgWordLength"`
+	AvgSentenceLength float64 `json:"avgSentenceLength"`
+	AvgSyllables      float64 `json:"avgSyllables"`
+
+	Readability Readability `json:"readability"`
+
+	// Duration is the time from the first to the end of the last segment and
+	// SpeakingTime the part of it covered by segments, both in seconds.
+	// They and WordsPerMinute are only set for timed documents.
+	Duration       float64 `json:"duration,omitempty"`
+	SpeakingTime   float64 `json:"speakingTime,omitempty"`
+	WordsPerMinute float64 `json:"wordsPerMinute,omitempty"`
+
+	Difficulty *Difficulty `json:"difficulty,omitempty"`
+}
+
+// Readability holds the classic readability formulas. They were designed for
+// English text and are only indicative for other languages.
+type Readability struct {
+	// FleschReadingEase is 0-100, higher is easier
+	FleschReadingEase float64 `json:"fleschReadingEase"`
+	// The others estimate the US school grade needed to understand the text
+	FleschKincaidGrade   float64 `json:"fleschKincaidGrade"`
+	GunningFog           float64 `json:"gunningFog"`
+	ColemanLiau          float64 `json:"colemanLiau"`
+	AutomatedReadability float64 `json:"automatedReadability"`
+	SMOG                 float64 `json:"smog"`
+}
+
+// ComputeStats measures the readability, lexical complexity and, for timed
+// documents, speaking rate of a document. The language selects the function
+// words and the word list for the difficulty estimate; the document's own
+// language takes precedence if it is detected or declared.
+func ComputeStats(doc *Document, lang string) *Stats {
+	lang = doc.Language(lang)
+	tokens := doc.Tokens()
+	s := &Stats{
+		Words:  len(tokens),
+		Unique: len(Frequencies(tokens)),
+	}
+	if s.Words == 0 {
+		return s
+	}
+
+	s.Sentences, s.SentencesEstimated = countSentences(doc)
+	if s.Sentences == 0 {
+		s.Sentences = 1
+	}
+
+	letters, polysyllables := 0, 0
+	for _, token := range tokens {
+		letters += countLetters(token)
+		n := countSyllables(token)
+		s.Syllables += n
+		if n >= 3 {
+			polysyllables++
+		}
+	}
+
+	words, sentences := float64(s.Words), float64(s.Sentences)
+	wordsPerSentence := words / sentences
+	syllablesPerWord := float64(s.Syllables) / words
+	lettersPerWord := float64(letters) / words
+
+	s.TypeTokenRatio = round(float64(s.Unique)/words, 3)
+	if function, ok := functionWords[baseLanguage(lang)]; ok {
+		content := 0
+		for _, token := range tokens {
+			if !function[token] {
+				content++
+			}
+		}
+		s.LexicalDensity = round(float64(content)/words, 3)
+	}
+	s.AvgWordLength = round(lettersPerWord, 2)
+	s.AvgSentenceLength = round(wordsPerSentence, 2)
+	s.AvgSyllables = round(syllablesPerWord, 2)
+
+	s.Readability = Readability{
+		FleschReadingEase:    round(206.835-1.015*wordsPerSentence-84.6*syllablesPerWord, 1),
+		FleschKincaidGrade:   round(0.39*wordsPerSentence+11.8*syllablesPerWord-15.59, 1),
+		GunningFog:           round(0.4*(wordsPerSentence+100*float64(polysyllables)/words), 1),
+		ColemanLiau:          round(0.0588*lettersPerWord*100-0.296*sentences/words*100-15.8, 1),
+		AutomatedReadability: round(4.71*lettersPerWord+0.5*wordsPerSentence-21.43, 1),
+		SMOG:                 round(1.043*math.Sqrt(float64(polysyllables)*30/sentences)+3.1291, 1),
+	}
+
+	if doc.Timed {
+		s.Duration, s.SpeakingTime = timing(doc.Segments)
+		if s.SpeakingTime > 0 {
+			s.WordsPerMinute = round(words/(s.SpeakingTime/60), 1)
+		}
+		s.Duration = round(s.Duration, 2)
+		s.SpeakingTime = round(s.SpeakingTime, 2)
+	}
+
+	s.Difficulty = EstimateDifficulty(doc, lang)
+	return s
+}
+
+// countSentences counts the sentences of a document. Paragraphs of untimed
+// documents always end a sentence, so that headings count as sentences too.
+// Text without sentence punctuation is counted one sentence per segment.
+func countSentences(doc *Document) (int, bool) {
+	var texts []string
+	if doc.Timed {
+		// Captions break lines mid-sentence, so join them first
+		texts = []string{doc.Text()}
+	} else {
+		for _, segment := range doc.Segments {
+			texts = append(texts, segment.Text)
+		}
+	}
+
+	count, punctuated := 0, false
+	for _, text := range texts {
+		if sentenceEnd.MatchString(text) {
+			punctuated = true
+		}
+		for _, sentence := range sentenceEnd.Split(text, -1) {
+			if len(Tokenize(sentence)) > 0 {
+				count++
+			}
+		}
+	}
+	if punctuated {
+		return count, false
+	}
+
+	count = 0
+	for _, segment := range doc.Segments {
+		if len(Tokenize(segment.Text)) > 0 {
+			count++
+		}
+	}
+	return count, true
+}
+
+// countSyllables estimates the syllables of a word by counting groups of
+// vowels, dropping the silent e of English words like "make" and "jumped"
+func countSyllables(word string) int {
+	count, inVowels := 0, false
+	for _, r := range word {
+		vowel := isVowel(r)
+		if vowel && !inVowels {
+			count++
+		}
+		inVowels = vowel
+	}
+
+	if count > 1 {
+		switch {
+		case strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && !strings.HasSuffix(word, "ee"):
+			count--
+		case strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "ted") && !strings.HasSuffix(word, "ded"):
+			count--
+		case strings.HasSuffix(word, "es") && !strings.HasSuffix(word, "ses") && !strings.HasSuffix(word, "ces") &&
+			!strings.HasSuffix(word, "zes") && !strings.HasSuffix(word, "ges") && !strings.HasSuffix(word, "shes") &&
+			!strings.HasSuffix(word, "ches") && !strings.HasSuffix(word, "xes"):
+			count--
+		}
+	}
+	return max(count, 1)
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyäöåáàâéèêëíìîïóòôúùûüýæøœ", unicode.ToLower(r))
+}
+
+func countLetters(word string) int {
+	n := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// timing returns the span of a timed document and the time covered by its
+// segments. Overlapping segments, common in automatic captions, are merged.
+func timing(segments []Segment) (duration, speaking float64) {
+	type interval struct{ start, end float64 }
+	var intervals []interval
+	for _, s := range segments {
+		if s.Duration > 0 && len(Tokenize(s.Text)) > 0 {
+			intervals = append(intervals, interval{s.Start, s.Start + s.Duration})
+		}
+	}
+	if len(intervals) == 0 {
+		return 0, 0
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+
+	current := intervals[0]
+	end := current.end
+	for _, next := range intervals[1:] {
+		if next.start > current.end {
+			speaking += current.end - current.start
+			current = next
+		} else if next.end > current.end {
+			current.end = next.end
+		}
+		end = math.Max(end, next.end)
+	}
+	speaking += current.end - current.start
+	return end - intervals[0].start, speaking
+}
+
+func round(x float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(x*p) / p
+}
+
+// functionWords are the closed-class words of each language: articles,
+// pronouns, prepositions, conjunctions, auxiliaries and particles
+var functionWords = map[string]map[string]bool{
+	"en": wordSet(`a about above across after against all along although am an and any are as at be
+		because been before being below between both but by can could did do does doing down during
+		each either every few for from had has have having he her hers herself him himself his how i
+		if in into is it its itself just may me might mine more most must my myself neither no nor not
+		of off on once only onto or other our ours ourselves out over own per shall she should since
+		so some such than that the their theirs them themselves then there these they this those
+		though through to too toward towards under until up upon us very was we were what whatever
+		when where whether which while who whom whose why will with within without would yet you
+		your yours yourself yourselves i'm you're he's she's it's we're they're i've you've we've
+		they've i'd you'd he'd she'd we'd they'd i'll you'll he'll she'll we'll they'll isn't aren't
+		wasn't weren't hasn't haven't hadn't doesn't don't didn't won't wouldn't can't cannot couldn't
+		shouldn't mustn't let's that's there's what's who's here's oh uh um`),
+	"fi": wordSet(`ja sekä tai vai mutta vaan kun jos että koska vaikka kuin niin myös eli
+		on ovat oli olivat ole olla olen olet olemme olette ei en et emme ette eivät ollut olleet
+		minä sinä hän me te he se ne tämä tuo nämä nuo joka jotka mikä mitkä kuka ketkä
+		minun sinun hänen meidän teidän heidän sen niiden tämän tuon näiden joiden minkä
+		minua sinua häntä meitä teitä heitä sitä niitä tätä tuota mitä jota mä sä mun sun
+		nyt sitten vielä jo aina kaikki mitään jotain jokin joku kanssa ilman mukaan kautta
+		jälkeen ennen aikana alla yli yllä luona takia vuoksi`),
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,124 @@
+package words
+
+import (
+	"testing"
+)
+
+func TestCountSyllables(t *testing.T) {
+	tests := map[string]int{
+		"cat":         1,
+		"make":        1,
+		"jumped":      1,
+		"wanted":      2,
+		"table":       2,
+		"boxes":       2,
+		"likes":       1,
+		"beautiful":   3,
+		"readability": 5,
+		"sauna":       2,
+		"the":         1,
+		"rhythm":      1,
+	}
+	for word, want := range tests {
+		if got := countSyllables(word); got != want {
+			t.Errorf("countSyllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	doc := &Document{Segments: paragraphs("The cat sat on the mat. The dog ran after the cat!\n\nA short heading")}
+	s := ComputeStats(doc, "en")
+
+	if s.Words != 15 || s.Unique != 11 || s.Sentences != 3 || s.SentencesEstimated {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.TypeTokenRatio != 0.733 {
+		t.Errorf("type-token ratio = %v, want 0.733", s.TypeTokenRatio)
+	}
+	// the, on, the, the, after, the and a are function words
+	if s.LexicalDensity != 0.533 {
+		t.Errorf("lexical density = %v, want 0.533", s.LexicalDensity)
+	}
+	if s.Readability.FleschReadingEase < 90 {
+		t.Errorf("simple text should be easy to read, got %+v", s.Readability)
+	}
+	if s.WordsPerMinute != 0 || s.Duration != 0 {
+		t.Errorf("untimed document should have no speaking rate: %+v", s)
+	}
+	// mat and short rank between 4,000 and 8,000 in the word list
+	if s.Difficulty == nil || s.Difficulty.Level != "B2" {
+		t.Errorf("difficulty = %+v, want B2", s.Difficulty)
+	}
+
+	hard := &Document{Segments: paragraphs("Notwithstanding considerable epistemological ramifications, the committee's deliberations remained inconclusive.")}
+	if s := ComputeStats(hard, "en"); s.Readability.FleschReadingEase > 0 || s.Difficulty.Level != "C2" {
+		t.Errorf("hard text scored as easy: %+v %+v", s.Readability, s.Difficulty)
+	}
+
+	if s := ComputeStats(&Document{Lang: "sv", Segments: paragraphs("Hej hej.")}, "en"); s.Difficulty != nil || s.LexicalDensity != 0 {
+		t.Errorf("document language should take precedence: %+v", s)
+	}
+}
+
+func TestComputeStatsTimed(t *testing.T) {
+	// Automatic captions overlap and have no punctuation
+	doc := &Document{Timed: true, Segments: []Segment{
+		{Text: "hello and welcome", Start: 0, Duration: 3},
+		{Text: "to this video about", Start: 2, Duration: 3},
+		{Text: "[Music]", Start: 5, Duration: 10},
+		{Text: "the sauna in Finland", Start: 20, Duration: 4},
+	}}
+	s := ComputeStats(doc, "en")
+
+	if !s.SentencesEstimated || s.Sentences != 4 {
+		t.Errorf("sentences = %d (estimated %v), want 4 estimated", s.Sentences, s.SentencesEstimated)
+	}
+	// [Music] counts as a word, so it is spoken during 5-15 too
+	if s.Duration != 24 || s.SpeakingTime != 19 {
+		t.Errorf("duration = %v, speaking time = %v, want 24 and 19", s.Duration, s.SpeakingTime)
+	}
+	if s.WordsPerMinute != 37.9 {
+		t.Errorf("words per minute = %v, want 37.9", s.WordsPerMinute)
+	}
+	if s.Difficulty.Names != 1 {
+		t.Errorf("Finland should be left out as a name: %+v", s.Difficulty)
+	}
+}
+
+func TestVocabularyStems(t *testing.T) {
+	v, ok := loadVocabulary("en-US")
+	if !ok {
+		t.Fatal("no English word list")
+	}
+	for _, word := range []string{"studies", "stopped", "running", "cats", "quickly", "making", "teacher's", "happiness"} {
+		if level := v.level(word, "en"); level == len(Levels)-1 {
+			t.Errorf("%q was not matched to its stem", word)
+		}
+	}
+}
+
+func TestVocabularyLevels(t *testing.T) {
+	v, ok := loadVocabulary("en")
+	if !ok {
+		t.Fatal("no English word list")
+	}
+	// The first and last word of each band, by rank in the source list
+	for word, want := range map[string]string{
+		"you":          "A1",
+		"month":        "A1",
+		"visit":        "A2",
+		"chick":        "A2",
+		"threatening":  "B1",
+		"aspirin":      "B1",
+		"academy":      "B2",
+		"tummy":        "B2",
+		"tibet":        "C1",
+		"enlightening": "C1",
+		"energetic":    "C2",
+	} {
+		if got := Levels[v.level(word, "en")]; got != want {
+			t.Errorf("level of %q = %s, want %s", word, got, want)
+		}
+	}
+}
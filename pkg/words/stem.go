@@ -0,0 +1,245 @@
+package words
+
+import "strings"
+
+// Stem reduces a word to its stem so that inflected forms can be matched,
+// e.g. "connected", "connecting" and "connection" all become "connect".
+// English words are stemmed with the Porter algorithm; words of other
+// languages are returned unchanged.
+func Stem(word, lang string) string {
+	if baseLanguage(lang) == "en" {
+		return porterStem(word)
+	}
+	return word
+}
+
+// porter holds the state of the Porter stemmer: the word being stemmed and
+// the end j of the stem left when a suffix has been matched
+type porter struct {
+	b []byte
+	j int
+}
+
+// porterStem implements the Porter stemming algorithm as described in
+// M.F. Porter, "An algorithm for suffix stripping", Program 14(3), 1980.
+// Words that are short or not plain ASCII letters are returned unchanged.
+func porterStem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	// Stem the part before an apostrophe, so "teacher's" stems like "teacher"
+	if i := strings.IndexByte(word, '\''); i > 0 {
+		word = word[:i]
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	p := &porter{b: []byte(word)}
+	p.step1ab()
+	if len(p.b) > 2 {
+		p.step1c()
+		p.step2()
+		p.step3()
+		p.step4()
+		p.step5()
+	}
+	return string(p.b)
+}
+
+// cons reports whether b[i] is a consonant. Y is a consonant unless it
+// follows a consonant, as in "sky" versus "toy".
+func (p *porter) cons(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !p.cons(i-1)
+	}
+	return true
+}
+
+// m measures the number of vowel-consonant sequences in b[:j]
+func (p *porter) m() int {
+	n, i := 0, 0
+	for ; i < p.j && p.cons(i); i++ {
+	}
+	for i < p.j {
+		for ; i < p.j && !p.cons(i); i++ {
+		}
+		if i >= p.j {
+			break
+		}
+		n++
+		for ; i < p.j && p.cons(i); i++ {
+		}
+	}
+	return n
+}
+
+// vowelInStem reports whether b[:j] contains a vowel
+func (p *porter) vowelInStem() bool {
+	for i := 0; i < p.j; i++ {
+		if !p.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleC reports whether b[:i+1] ends with a double consonant
+func (p *porter) doubleC(i int) bool {
+	return i >= 1 && p.b[i] == p.b[i-1] && p.cons(i)
+}
+
+// cvc reports whether b[:i+1] ends consonant-vowel-consonant where the last
+// consonant is not w, x or y, which marks short stems like "hop" and "fil"
+func (p *porter) cvc(i int) bool {
+	if i < 2 || !p.cons(i) || p.cons(i-1) || !p.cons(i-2) {
+		return false
+	}
+	switch p.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends reports whether the word ends with s, and if so sets j to the end of the stem
+func (p *porter) ends(s string) bool {
+	if !strings.HasSuffix(string(p.b), s) {
+		return false
+	}
+	p.j = len(p.b) - len(s)
+	return true
+}
+
+// setTo replaces the suffix after j with s
+func (p *porter) setTo(s string) {
+	p.b = append(p.b[:p.j], s...)
+}
+
+// replace replaces the suffix after j with s if the stem has a measure above 0
+func (p *porter) replace(s string) {
+	if p.m() > 0 {
+		p.setTo(s)
+	}
+}
+
+// step1ab removes plurals and -ed or -ing
+func (p *porter) step1ab() {
+	if p.b[len(p.b)-1] == 's' {
+		switch {
+		case p.ends("sses"):
+			p.b = p.b[:len(p.b)-2]
+		case p.ends("ies"):
+			p.setTo("i")
+		case len(p.b) > 1 && p.b[len(p.b)-2] != 's':
+			p.b = p.b[:len(p.b)-1]
+		}
+	}
+
+	if p.ends("eed") {
+		if p.m() > 0 {
+			p.b = p.b[:len(p.b)-1]
+		}
+		return
+	}
+	if !(p.ends("ed") || p.ends("ing")) || !p.vowelInStem() {
+		return
+	}
+
+	p.b = p.b[:p.j]
+	p.j = len(p.b)
+	switch {
+	case p.ends("at"):
+		p.setTo("ate")
+	case p.ends("bl"):
+		p.setTo("ble")
+	case p.ends("iz"):
+		p.setTo("ize")
+	case p.doubleC(len(p.b) - 1):
+		switch p.b[len(p.b)-1] {
+		case 'l', 's', 'z':
+		default:
+			p.b = p.b[:len(p.b)-1]
+		}
+	default:
+		p.j = len(p.b)
+		if p.m() == 1 && p.cvc(len(p.b)-1) {
+			p.b = append(p.b, 'e')
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem
+func (p *porter) step1c() {
+	if p.ends("y") && p.vowelInStem() {
+		p.b[len(p.b)-1] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones, e.g. -ization to -ize
+func (p *porter) step2() {
+	for _, r := range [][2]string{
+		{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+		{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"},
+		{"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"},
+		{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"},
+		{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}, {"logi", "log"},
+	} {
+		if p.ends(r[0]) {
+			p.replace(r[1])
+			return
+		}
+	}
+}
+
+// step3 handles -ic-, -full, -ness and the like
+func (p *porter) step3() {
+	for _, r := range [][2]string{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+		{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+	} {
+		if p.ends(r[0]) {
+			p.replace(r[1])
+			return
+		}
+	}
+}
+
+// step4 removes -ant, -ence and the like from stems with a measure above 1
+func (p *porter) step4() {
+	for _, suffix := range []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+		"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	} {
+		if !p.ends(suffix) {
+			continue
+		}
+		if suffix == "ion" && (p.j == 0 || (p.b[p.j-1] != 's' && p.b[p.j-1] != 't')) {
+			return
+		}
+		if p.m() > 1 {
+			p.b = p.b[:p.j]
+		}
+		return
+	}
+}
+
+// step5 removes a final -e and reduces a final -ll to -l in long stems
+func (p *porter) step5() {
+	p.j = len(p.b)
+	if p.b[len(p.b)-1] == 'e' {
+		p.j = len(p.b) - 1
+		if m := p.m(); m > 1 || m == 1 && !p.cvc(len(p.b)-2) {
+			p.b = p.b[:len(p.b)-1]
+		}
+	}
+	p.j = len(p.b)
+	if p.b[len(p.b)-1] == 'l' && p.doubleC(len(p.b)-1) && p.m() > 1 {
+		p.b = p.b[:len(p.b)-1]
+	}
+}
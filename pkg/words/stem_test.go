@@ -0,0 +1,61 @@
+package words
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	// Examples from Porter's paper
+	tests := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"ties":            "ti",
+		"cats":            "cat",
+		"feed":            "feed",
+		"agreed":          "agre",
+		"plastered":       "plaster",
+		"bled":            "bled",
+		"motoring":        "motor",
+		"sing":            "sing",
+		"conflated":       "conflat",
+		"troubled":        "troubl",
+		"sized":           "size",
+		"hopping":         "hop",
+		"falling":         "fall",
+		"hissing":         "hiss",
+		"filing":          "file",
+		"happy":           "happi",
+		"sky":             "sky",
+		"relational":      "relat",
+		"conditional":     "condit",
+		"rational":        "ration",
+		"digitizer":       "digit",
+		"vietnamization":  "vietnam",
+		"operator":        "oper",
+		"decisiveness":    "decis",
+		"hopefulness":     "hope",
+		"sensibiliti":     "sensibl",
+		"electrical":      "electr",
+		"goodness":        "good",
+		"allowance":       "allow",
+		"adjustable":      "adjust",
+		"adoption":        "adopt",
+		"effective":       "effect",
+		"probate":         "probat",
+		"rate":            "rate",
+		"controll":        "control",
+		"roll":            "roll",
+		"generalizations": "gener",
+		"oscillators":     "oscil",
+		"teacher's":       "teacher",
+		"well-known":      "well-known",
+		"naïve":           "naïve",
+	}
+	for word, want := range tests {
+		if got := Stem(word, "en-GB"); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+
+	if got := Stem("saunassa", "fi"); got != "saunassa" {
+		t.Errorf("Stem should leave other languages alone, got %q", got)
+	}
+}
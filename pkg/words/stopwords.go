@@ -0,0 +1,55 @@
+package words
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+//go:embed stopwords/*.txt
+var stopwordLists embed.FS
+
+var (
+	stopWordsMu sync.Mutex
+	stopWords   = make(map[string]map[string]bool)
+)
+
+// StopWords returns the words of a language that carry little meaning of
+// their own: its function words and the common words of the bundled stop
+// word list. It returns nil for languages without either.
+func StopWords(lang string) map[string]bool {
+	lang = baseLanguage(lang)
+
+	stopWordsMu.Lock()
+	defer stopWordsMu.Unlock()
+	if words, ok := stopWords[lang]; ok {
+		return words
+	}
+
+	var words map[string]bool
+	if function, ok := functionWords[lang]; ok {
+		words = make(map[string]bool, len(function))
+		for w := range function {
+			words[w] = true
+		}
+	}
+	if f, err := stopwordLists.Open("stopwords/" + lang + ".txt"); err == nil {
+		defer f.Close()
+		if words == nil {
+			words = make(map[string]bool)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			for _, w := range strings.Fields(line) {
+				words[w] = true
+			}
+		}
+	}
+	stopWords[lang] = words
+	return words
+}
@@ -0,0 +1,248 @@
+package words
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Timeline defaults
+const (
+	DefaultTimelineWindow = 30.0
+	DefaultTimelineStep   = 5.0
+	DefaultMinGap         = 3.0
+	DefaultBurstFactor    = 1.5
+
+	// MinTimelineStep is the shortest step between windows in seconds
+	MinTimelineStep = 0.5
+	// MaxTimelinePoints caps the number of windows of a timeline; the step
+	// is made longer for documents that would need more
+	MaxTimelinePoints = 10000
+)
+
+// nonSpeech matches captions that describe sounds rather than speech, such
+// as "[Music]", "(applause)" or "♪♪"
+var nonSpeech = regexp.MustCompile(`^\s*(\[[^\]]*\]|\([^)]*\)|[♪♫\s]+)\s*$`)
+
+// TimelineOptions configures AnalyzeTimeline. Zero values select the defaults.
+type TimelineOptions struct {
+	// Window is the length of the sliding window in seconds
+	Window float64
+	// Step is the time between the starts of consecutive windows in
+	// seconds, at least MinTimelineStep
+	Step float64
+	// MinGap is the shortest stretch without speech reported as a gap
+	MinGap float64
+	// BurstFactor is how many times faster than average speech has to be
+	// to count as a burst
+	BurstFactor float64
+}
+
+// Timeline describes how the speaking rate of a timed document changes
+// over time
+type Timeline struct {
+	// Duration is the end of the last caption in seconds
+	Duration float64 `json:"duration"`
+	Words    int     `json:"words"`
+	// WordsPerMinute is the average rate while speaking
+	WordsPerMinute float64 `json:"wordsPerMinute"`
+	Window         float64 `json:"window"`
+	Step           float64 `json:"step"`
+
+	Points []TimelinePoint `json:"points"`
+	Gaps   []Gap           `json:"gaps"`
+	Bursts []Burst         `json:"bursts"`
+}
+
+// TimelinePoint is the speaking rate in one window
+type TimelinePoint struct {
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	Words          float64 `json:"words"`
+	WordsPerMinute float64 `json:"wordsPerMinute"`
+}
+
+// Gap is a stretch without speech, such as a pause or music
+type Gap struct {
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Duration float64 `json:"duration"`
+	// Label is the caption describing the gap, e.g. "[Music]", if any
+	Label string `json:"label,omitempty"`
+}
+
+// Burst is a stretch of consecutive windows with unusually fast speech
+type Burst struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// PeakWordsPerMinute is the fastest rate of the windows in the burst
+	PeakWordsPerMinute float64 `json:"peakWordsPerMinute"`
+}
+
+// speech is a caption with the number of words spoken in it
+type speech struct {
+	start, end float64
+	words      int
+}
+
+// AnalyzeTimeline computes the speaking rate of a timed document over
+// sliding windows, the gaps between captions and the bursts of fast speech.
+// The words of each caption are taken to be spread evenly over its duration.
+// Documents without timing get an empty timeline.
+func AnalyzeTimeline(doc *Document, opts TimelineOptions) *Timeline {
+	if opts.Window <= 0 {
+		opts.Window = DefaultTimelineWindow
+	}
+	if opts.Step <= 0 {
+		opts.Step = DefaultTimelineStep
+	}
+	opts.Step = math.Max(opts.Step, MinTimelineStep)
+	if opts.MinGap <= 0 {
+		opts.MinGap = DefaultMinGap
+	}
+	if opts.BurstFactor <= 0 {
+		opts.BurstFactor = DefaultBurstFactor
+	}
+
+	t := &Timeline{Window: opts.Window, Step: opts.Step, Points: []TimelinePoint{}, Gaps: []Gap{}, Bursts: []Burst{}}
+	if !doc.Timed || len(doc.Segments) == 0 {
+		return t
+	}
+
+	var captions []speech
+	var speaking []Segment
+	for _, segment := range doc.Segments {
+		n := len(Tokenize(segment.Text))
+		if n == 0 || nonSpeech.MatchString(segment.Text) {
+			continue
+		}
+		captions = append(captions, speech{segment.Start, segment.Start + segment.Duration, n})
+		speaking = append(speaking, segment)
+	}
+
+	for _, segment := range doc.Segments {
+		t.Duration = math.Max(t.Duration, segment.Start+segment.Duration)
+	}
+	t.Duration = round(t.Duration, 2)
+	// The windows start at 0 and every step up to the end
+	if t.Duration/t.Step >= MaxTimelinePoints {
+		t.Step = math.Ceil(t.Duration/(MaxTimelinePoints-1)*100) / 100
+	}
+	for _, c := range captions {
+		t.Words += c.words
+	}
+	if _, speakingTime := timing(speaking); speakingTime > 0 {
+		t.WordsPerMinute = round(float64(t.Words)/(speakingTime/60), 1)
+	}
+
+	t.Points = windows(captions, t.Duration, t.Window, t.Step)
+	t.Gaps = gaps(doc.Segments, t.Duration, opts.MinGap)
+	t.Bursts = bursts(t.Points, t.WordsPerMinute*opts.BurstFactor)
+	return t
+}
+
+// windows counts the words spoken in each window. The last window ends
+// with the document, and documents shorter than a window get just one.
+func windows(captions []speech, duration, window, step float64) []TimelinePoint {
+	points := []TimelinePoint{}
+	if duration <= 0 {
+		return points
+	}
+	for start := 0.0; ; start += step {
+		end := math.Min(start+window, duration)
+		var words float64
+		for _, c := range captions {
+			words += c.wordsBetween(start, end)
+		}
+		points = append(points, TimelinePoint{
+			Start:          round(start, 2),
+			End:            round(end, 2),
+			Words:          round(words, 1),
+			WordsPerMinute: round(words/((end-start)/60), 1),
+		})
+		if start+window >= duration {
+			return points
+		}
+	}
+}
+
+// wordsBetween returns the share of a caption's words spoken between two times
+func (s speech) wordsBetween(start, end float64) float64 {
+	if s.end <= s.start {
+		if s.start >= start && s.start < end {
+			return float64(s.words)
+		}
+		return 0
+	}
+	overlap := math.Min(s.end, end) - math.Max(s.start, start)
+	if overlap <= 0 {
+		return 0
+	}
+	return float64(s.words) * overlap / (s.end - s.start)
+}
+
+// gaps finds the stretches of at least minGap seconds not covered by any
+// spoken caption, including before the first one and after the last one up
+// to the end of the document. Captions describing sounds do not count as
+// speech but label the gap they fall in.
+func gaps(segments []Segment, duration, minGap float64) []Gap {
+	sorted := append([]Segment(nil), segments...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	found := []Gap{}
+	covered, label := 0.0, ""
+	for _, segment := range sorted {
+		if nonSpeech.MatchString(segment.Text) {
+			if label == "" && segment.Start >= covered {
+				label = strings.TrimSpace(segment.Text)
+			}
+			continue
+		}
+		if len(Tokenize(segment.Text)) == 0 {
+			continue
+		}
+		if segment.Start-covered >= minGap {
+			found = append(found, Gap{
+				Start:    round(covered, 2),
+				End:      round(segment.Start, 2),
+				Duration: round(segment.Start-covered, 2),
+				Label:    label,
+			})
+		}
+		covered = math.Max(covered, segment.Start+segment.Duration)
+		label = ""
+	}
+	if duration-covered >= minGap {
+		found = append(found, Gap{
+			Start:    round(covered, 2),
+			End:      round(duration, 2),
+			Duration: round(duration-covered, 2),
+			Label:    label,
+		})
+	}
+	return found
+}
+
+// bursts merges consecutive windows faster than the threshold
+func bursts(points []TimelinePoint, threshold float64) []Burst {
+	found := []Burst{}
+	if threshold <= 0 {
+		return found
+	}
+	var current *Burst
+	for _, p := range points {
+		if p.WordsPerMinute < threshold {
+			current = nil
+			continue
+		}
+		if current == nil {
+			found = append(found, Burst{Start: p.Start, End: p.End, PeakWordsPerMinute: p.WordsPerMinute})
+			current = &found[len(found)-1]
+			continue
+		}
+		current.End = p.End
+		current.PeakWordsPerMinute = math.Max(current.PeakWordsPerMinute, p.WordsPerMinute)
+	}
+	return found
+}
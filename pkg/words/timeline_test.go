@@ -0,0 +1,76 @@
+package words
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeTimeline(t *testing.T) {
+	doc := &Document{
+		Timed: true,
+		Segments: []Segment{
+			{Text: "[Music]", Start: 0, Duration: 5},
+			{Text: "one two three four five", Start: 5, Duration: 5},
+			{Text: "six seven", Start: 10, Duration: 5},
+			{Text: "[Applause]", Start: 16, Duration: 2},
+			{Text: strings.Repeat("fast ", 20), Start: 20, Duration: 5},
+		},
+	}
+
+	tl := AnalyzeTimeline(doc, TimelineOptions{Window: 5})
+	if tl.Duration != 25 || tl.Words != 27 || tl.WordsPerMinute != 108 || tl.Step != DefaultTimelineStep {
+		t.Errorf("unexpected totals: %+v", tl)
+	}
+
+	var rates []float64
+	for _, p := range tl.Points {
+		rates = append(rates, p.WordsPerMinute)
+	}
+	if want := []float64{0, 60, 24, 0, 240}; !reflect.DeepEqual(rates, want) {
+		t.Errorf("rates = %v, want %v", rates, want)
+	}
+
+	wantGaps := []Gap{
+		{Start: 0, End: 5, Duration: 5, Label: "[Music]"},
+		{Start: 15, End: 20, Duration: 5, Label: "[Applause]"},
+	}
+	if !reflect.DeepEqual(tl.Gaps, wantGaps) {
+		t.Errorf("gaps = %+v, want %+v", tl.Gaps, wantGaps)
+	}
+	if want := []Burst{{Start: 20, End: 25, PeakWordsPerMinute: 240}}; !reflect.DeepEqual(tl.Bursts, want) {
+		t.Errorf("bursts = %+v, want %+v", tl.Bursts, want)
+	}
+
+	// Overlapping windows share the words of the captions they cover
+	tl = AnalyzeTimeline(doc, TimelineOptions{Window: 10, Step: 5})
+	if len(tl.Points) != 4 || tl.Points[3] != (TimelinePoint{Start: 15, End: 25, Words: 20, WordsPerMinute: 120}) {
+		t.Errorf("unexpected points: %+v", tl.Points)
+	}
+
+	// Silence after the last caption is a gap up to the end
+	doc.Segments = append(doc.Segments, Segment{Text: "[Music]", Start: 25, Duration: 10})
+	tl = AnalyzeTimeline(doc, TimelineOptions{Window: 5})
+	if last := tl.Gaps[len(tl.Gaps)-1]; last != (Gap{Start: 25, End: 35, Duration: 10, Label: "[Music]"}) {
+		t.Errorf("gaps = %+v, want a trailing gap", tl.Gaps)
+	}
+
+	for _, empty := range []*Document{{Segments: paragraphs("untimed")}, {Timed: true}} {
+		tl := AnalyzeTimeline(empty, TimelineOptions{})
+		if tl == nil || tl.Duration != 0 || len(tl.Points) != 0 || tl.Gaps == nil {
+			t.Errorf("expected an empty timeline, got %+v", tl)
+		}
+	}
+}
+
+func TestAnalyzeTimelineStep(t *testing.T) {
+	doc := &Document{Timed: true, Segments: []Segment{{Text: "hello there", Start: 0, Duration: 10}}}
+	if tl := AnalyzeTimeline(doc, TimelineOptions{Window: 1, Step: 0.000001}); tl.Step != MinTimelineStep || len(tl.Points) != 19 {
+		t.Errorf("step = %v with %d points, want the minimum step", tl.Step, len(tl.Points))
+	}
+
+	doc.Segments[0].Duration = 3 * MaxTimelinePoints
+	if tl := AnalyzeTimeline(doc, TimelineOptions{Window: 1, Step: 1}); tl.Step <= 3 || len(tl.Points) > MaxTimelinePoints {
+		t.Errorf("step = %v with %d points, want at most %d points", tl.Step, len(tl.Points), MaxTimelinePoints)
+	}
+}
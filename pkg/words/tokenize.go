@@ -0,0 +1,68 @@
+package words
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lower-cased words. A word is a run of letters,
+// digits and combining marks that contains at least one letter; apostrophes
+// and hyphens are kept inside words, as in "don't" and "sauna-ilta".
+func Tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	hasLetter := false
+
+	flush := func() {
+		// Drop joiners left dangling at the end, e.g. "saunas'"
+		for len(word) > 0 && isJoiner(word[len(word)-1]) {
+			word = word[:len(word)-1]
+		}
+		if len(word) > 0 && hasLetter {
+			tokens = append(tokens, strings.ToLower(string(word)))
+		}
+		word = word[:0]
+		hasLetter = false
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+			word = append(word, r)
+		case unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			word = append(word, r)
+		case isJoiner(r) && len(word) > 0 && i+1 < len(runes) && isWordRune(runes[i+1]):
+			word = append(word, normalizeJoiner(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+func isJoiner(r rune) bool {
+	switch r {
+	case '\'', '’', '-', '‐':
+		return true
+	}
+	return false
+}
+
+// normalizeJoiner maps typographic apostrophes and hyphens to ASCII
+func normalizeJoiner(r rune) rune {
+	switch r {
+	case '’':
+		return '\''
+	case '‐':
+		return '-'
+	}
+	return r
+}
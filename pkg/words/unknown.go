@@ -0,0 +1,106 @@
+package words
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxExampleWords is the longest sentence used as an example. Longer
+// sentences, as in captions without punctuation, give way to the segment.
+const maxExampleWords = 30
+
+// UnknownWord is a word of a document that is not in the known words,
+// together with where it first appears
+type UnknownWord struct {
+	// Word is the most frequent form of the word in the document
+	Word string `json:"word"`
+	Stem string `json:"stem"`
+	// Count is the number of occurrences of all forms
+	Count int `json:"count"`
+	// Forms are the forms found in the document, when there is more than one
+	Forms []string `json:"forms,omitempty"`
+	// Start is the time of the first occurrence in seconds, for timed documents
+	Start *float64 `json:"start,omitempty"`
+	// Example is the sentence of the first occurrence
+	Example string `json:"example"`
+}
+
+// occurrence is a token of a document with its position
+type occurrence struct {
+	token    string
+	sentence int
+	segment  int
+	// name is set when the word is capitalized within a sentence
+	name bool
+	// capitalized is set when the word is capitalized at all
+	capitalized bool
+}
+
+// UnknownWords lists the words of a document that are not known, ranked by
+// frequency. English words are matched on their stems, so knowing "run" also
+// covers "runs" and "running"; other languages are matched as written, since
+// Stem only handles English. Names, by the rule of nameTokens, are left out.
+// The document's detected or declared language takes precedence over lang.
+func UnknownWords(doc *Document, known KnownWords, lang string) []UnknownWord {
+	lang = doc.Language(lang)
+	knownStems := known.Stems(lang)
+	sentences, occurrences := scanSentences(doc)
+	names := nameTokens(occurrences)
+
+	byStem := make(map[string]*UnknownWord)
+	forms := make(map[string]map[string]int)
+	var order []string
+	for _, o := range occurrences {
+		if names[o.token] {
+			continue
+		}
+		stem := Stem(o.token, lang)
+		if knownStems[stem] {
+			continue
+		}
+
+		w, ok := byStem[stem]
+		if !ok {
+			w = &UnknownWord{Stem: stem, Example: example(doc, sentences[o.sentence], o.segment)}
+			if doc.Timed {
+				start := doc.Segments[o.segment].Start
+				w.Start = &start
+			}
+			byStem[stem] = w
+			forms[stem] = make(map[string]int)
+			order = append(order, stem)
+		}
+		w.Count++
+		forms[stem][o.token]++
+	}
+
+	unknown := make([]UnknownWord, 0, len(order))
+	for _, stem := range order {
+		w := byStem[stem]
+		for form, count := range forms[stem] {
+			w.Forms = append(w.Forms, form)
+			if best := forms[stem][w.Word]; count > best || count == best && form < w.Word {
+				w.Word = form
+			}
+		}
+		sort.Strings(w.Forms)
+		if len(w.Forms) == 1 {
+			w.Forms = nil
+		}
+		unknown = append(unknown, *w)
+	}
+	// Stable so that equally frequent words keep the order they appear in
+	sort.SliceStable(unknown, func(i, j int) bool {
+		return unknown[i].Count > unknown[j].Count
+	})
+	return unknown
+}
+
+// example returns the sentence to show for an occurrence, or the text of its
+// segment when the sentence is too long to make a good example
+func example(doc *Document, s Sentence, segment int) string {
+	if s.Words > maxExampleWords {
+		return strings.TrimSpace(doc.Segments[segment].Text)
+	}
+	return s.Text
+}
@@ -0,0 +1,113 @@
+package words
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKnownWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vocab.yaml")
+	known, err := LoadKnownWords(path)
+	if err != nil || len(known) != 0 {
+		t.Fatalf("missing file should give no known words: %v %v", known, err)
+	}
+
+	if added := known.Add("en-US", "Run", "house", "run", ""); added != 2 {
+		t.Errorf("added %d words, want 2", added)
+	}
+	known.Add("fi", "sauna")
+	if err := known.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	known, err = LoadKnownWords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"house", "run"}; !reflect.DeepEqual(known.Words("en"), want) {
+		t.Errorf("Words(en) = %v, want %v", known.Words("en"), want)
+	}
+	if !known.Stems("en")["hous"] {
+		t.Errorf("expected stems of known words, got %v", known.Stems("en"))
+	}
+
+	if removed := known.Remove("fi", "sauna", "löyly"); removed != 1 {
+		t.Errorf("removed %d words, want 1", removed)
+	}
+	if _, ok := known["fi"]; ok {
+		t.Errorf("empty language should be dropped: %v", known)
+	}
+}
+
+func TestReadWordList(t *testing.T) {
+	list := "# my words\nHouse\n\n\"tree\",\"<b>puu</b>\",tags\nrun\tjuosta\n"
+	got, err := ReadWordList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"house", "tree", "run"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadWordList = %v, want %v", got, want)
+	}
+}
+
+func TestUnknownWords(t *testing.T) {
+	doc := &Document{
+		Title:    "abc",
+		Source:   SourceYouTube,
+		Location: "https://www.youtube.com/watch?v=abc",
+		Timed:    true,
+		Segments: []Segment{
+			{Text: "Today we run to the", Start: 1},
+			{Text: "sauna. Running is fun and Matti runs", Start: 4},
+			{Text: "every day. Saunas are hot!", Start: 65},
+		},
+	}
+	known := KnownWords{"en": {"we", "to", "the", "is", "and", "are", "every", "day", "fun"}}
+
+	unknown := UnknownWords(doc, known, "en")
+	var got []string
+	for _, w := range unknown {
+		got = append(got, w.Word)
+	}
+	// Matti is a name, Today only capitalized because it starts a sentence
+	if want := []string{"run", "sauna", "today", "hot"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unknown words = %v, want %v", got, want)
+	}
+
+	run := unknown[0]
+	if run.Count != 3 || !reflect.DeepEqual(run.Forms, []string{"run", "running", "runs"}) || *run.Start != 1 {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if run.Example != "Today we run to the sauna." {
+		t.Errorf("example = %q", run.Example)
+	}
+	if hot := unknown[3]; *hot.Start != 65 || hot.Example != "Saunas are hot!" {
+		t.Errorf("unexpected hot: %+v", hot)
+	}
+
+	var b strings.Builder
+	if err := WriteAnki(&b, doc, unknown[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := "#separator:Comma\n#html:true\n#columns:Front,Back,Tags\n#tags column:3\n" +
+		`run,"Today we <b>run</b> to the sauna.<br><a href=""https://www.youtube.com/watch?v=abc&amp;t=1s"">abc 0:01</a>",sanoja youtube` + "\n"
+	if b.String() != want {
+		t.Errorf("Anki deck =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestUnknownWordsUnstemmed(t *testing.T) {
+	// Only English is stemmed, so other inflected forms are separate words
+	doc := &Document{Lang: "fi", Segments: paragraphs("Sauna on lämmin. Istumme saunassa.")}
+	known := KnownWords{"fi": {"sauna", "on", "lämmin", "istumme"}}
+
+	var got []string
+	for _, w := range UnknownWords(doc, known, "fi") {
+		got = append(got, w.Word)
+	}
+	if want := []string{"saunassa"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown words = %v, want %v", got, want)
+	}
+}
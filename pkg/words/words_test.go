@@ -0,0 +1,102 @@
+package words
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"Hello and welcome to this video.":             {"hello", "and", "welcome", "to", "this", "video"},
+		`The word "sana" means word.`:                  {"the", "word", "sana", "means", "word"},
+		"Let's get started! It’s a well-known fact.":   {"let's", "get", "started", "it's", "a", "well-known", "fact"},
+		"Sauna-ilta klo 18.30 – tervetuloa, 3 saunaa!": {"sauna-ilta", "klo", "tervetuloa", "saunaa"},
+		"Über-cool -- 'quoted' COVID-19 saunas' naïve": {"über-cool", "quoted", "covid-19", "saunas", "naïve"},
+		"  ": nil,
+	}
+	for input, want := range tests {
+		if got := Tokenize(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNGrams(t *testing.T) {
+	tokens := Tokenize("sauna on kuuma ja sauna on hyvä")
+
+	want := []Count{{"on", 2}, {"sauna", 2}, {"hyvä", 1}, {"ja", 1}, {"kuuma", 1}}
+	if got := Frequencies(tokens); !reflect.DeepEqual(got, want) {
+		t.Errorf("Frequencies = %v, want %v", got, want)
+	}
+
+	bigrams := NGrams(tokens, 2)
+	if len(bigrams) != 5 || bigrams[0] != (Count{"sauna on", 2}) {
+		t.Errorf("bigrams = %v", bigrams)
+	}
+	if got := NGrams(tokens, 8); got != nil {
+		t.Errorf("expected no 8-grams of 7 tokens, got %v", got)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(path, []byte("Sauna on kuuma.\r\n\r\nLöyly on hyvä.\n\n\n"), 0o644)
+
+	doc, err := FileSource{Path: path}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "notes.txt" || doc.Source != SourceFile || doc.Timed || len(doc.Segments) != 2 {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	analysis := Analyze(doc, 1, 1)
+	if analysis.Tokens != 6 || analysis.Unique != 5 || !reflect.DeepEqual(analysis.Terms, []Count{{"on", 2}}) {
+		t.Errorf("unexpected analysis: %+v", analysis)
+	}
+}
+
+func TestReaderSource(t *testing.T) {
+	doc, err := ReaderSource{Name: "stdin", Reader: strings.NewReader("one\n\ntwo three")}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Text() != "one\ntwo three" || doc.Source != SourceStdin {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+}
+
+func TestDocumentLanguage(t *testing.T) {
+	doc := &Document{Lang: "en", Segments: []Segment{{Text: "Saunominen on tärkeä osa suomalaista kulttuuria."}}}
+	if lang := doc.Language("sv"); lang != "fi" {
+		t.Errorf("Language() = %s, want the detected fi over the declared en", lang)
+	}
+	if !doc.DetectLanguage().Mismatch(doc.Lang) {
+		t.Error("expected the declared en to be a mismatch")
+	}
+
+	short := &Document{Lang: "fi", Segments: []Segment{{Text: "Hei!"}}}
+	if lang := short.Language("sv"); lang != "fi" {
+		t.Errorf("Language() = %s, want the declared fi for text too short to identify", lang)
+	}
+	short.Lang = ""
+	if lang := short.Language("sv"); lang != "sv" {
+		t.Errorf("Language() = %s, want the fallback sv", lang)
+	}
+
+	doc.AnalysisLang = "en"
+	if lang := doc.Language("sv"); lang != "en" {
+		t.Errorf("Language() = %s, want the chosen en over the detected fi", lang)
+	}
+
+	estonian := &Document{Lang: "et", Segments: []Segment{{Text: "Tere kõigile ja tere tulemast minu kanalile täna räägime toidust"}}}
+	if lang := estonian.Language("en"); lang != "et" {
+		t.Errorf("Language() = %s, want the declared et, which detection does not support", lang)
+	}
+	if estonian.DetectLanguage().Mismatch(estonian.Lang) {
+		t.Error("an unsupported declared language should not be a mismatch")
+	}
+}